@@ -0,0 +1,183 @@
+package cliview
+
+import (
+	"reflect"
+)
+
+// AggregateFunc reduces the values of a column to the value displayed in the
+// table footer. nil values are included, so the function decides how to
+// treat missing data. Table.Stream keeps only running results for the
+// built-in aggregates, other functions get all the values of the column,
+// which are kept in memory until Close.
+type AggregateFunc func(values []interface{}) interface{}
+
+var (
+	AggregateSum   AggregateFunc = aggregateSum
+	AggregateAvg   AggregateFunc = aggregateAvg
+	AggregateMin   AggregateFunc = aggregateMin
+	AggregateMax   AggregateFunc = aggregateMax
+	AggregateCount AggregateFunc = aggregateCount
+)
+
+// numbers extracts the numeric values. sameType is the type shared by all
+// of them, or nil if they have different types.
+func numbers(values []interface{}) (nums []reflect.Value, sameType reflect.Type) {
+	for _, v := range values {
+		if class, rv := sortClass(v); class == sortNumber {
+			if len(nums) == 0 {
+				sameType = rv.Type()
+			} else if sameType != rv.Type() {
+				sameType = nil
+			}
+			nums = append(nums, rv)
+		}
+	}
+	return
+}
+
+// aggregateSum adds up the numeric values. The sum has the type of the
+// values if they all have the same integer type (e.g. time.Duration),
+// otherwise it is a float64.
+func aggregateSum(values []interface{}) interface{} {
+	nums, typ := numbers(values)
+	if len(nums) == 0 {
+		return nil
+	}
+	if typ != nil && isIntKind(typ.Kind()) {
+		var sum int64
+		for _, v := range nums {
+			sum += v.Int()
+		}
+		return reflect.ValueOf(sum).Convert(typ).Interface()
+	}
+	if typ != nil && isUintKind(typ.Kind()) {
+		var sum uint64
+		for _, v := range nums {
+			sum += v.Uint()
+		}
+		return reflect.ValueOf(sum).Convert(typ).Interface()
+	}
+	var sum float64
+	for _, v := range nums {
+		sum += numberFloat(v)
+	}
+	return sum
+}
+
+// aggregateAvg returns the float64 average of the numeric values.
+func aggregateAvg(values []interface{}) interface{} {
+	nums, _ := numbers(values)
+	if len(nums) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, v := range nums {
+		sum += numberFloat(v)
+	}
+	return sum / float64(len(nums))
+}
+
+func aggregateExtreme(values []interface{}, sign int) interface{} {
+	var result interface{}
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if result == nil || CompareValues(v, result)*sign > 0 {
+			result = v
+		}
+	}
+	return result
+}
+
+// aggregateMin returns the smallest non-nil value according to CompareValues.
+func aggregateMin(values []interface{}) interface{} {
+	return aggregateExtreme(values, -1)
+}
+
+// aggregateMax returns the largest non-nil value according to CompareValues.
+func aggregateMax(values []interface{}) interface{} {
+	return aggregateExtreme(values, 1)
+}
+
+// aggregateCount counts the non-nil values.
+func aggregateCount(values []interface{}) interface{} {
+	count := 0
+	for _, v := range values {
+		if v != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func funcPointer(f AggregateFunc) uintptr {
+	return reflect.ValueOf(f).Pointer()
+}
+
+var (
+	mergingAggregates = map[uintptr]bool{
+		funcPointer(aggregateSum): true,
+		funcPointer(aggregateMin): true,
+		funcPointer(aggregateMax): true,
+	}
+	countAggregate = funcPointer(aggregateCount)
+	avgAggregate   = funcPointer(aggregateAvg)
+)
+
+// runningAggregate aggregates the values of a column one at a time. The
+// built-in aggregates keep a running sum, count, minimum or maximum, other
+// functions get all the values, which are kept until the result is taken.
+type runningAggregate struct {
+	f      AggregateFunc
+	merge  bool // f of the running result and a value is the new result
+	count  bool
+	avg    bool
+	result interface{}
+	n      int
+	sum    float64
+	values []interface{}
+}
+
+func newRunningAggregate(f AggregateFunc) *runningAggregate {
+	ptr := funcPointer(f)
+	return &runningAggregate{
+		f:     f,
+		merge: mergingAggregates[ptr],
+		count: ptr == countAggregate,
+		avg:   ptr == avgAggregate,
+	}
+}
+
+func (a *runningAggregate) add(v interface{}) {
+	switch {
+	case a.merge:
+		a.result = a.f([]interface{}{a.result, v})
+	case a.count:
+		if v != nil {
+			a.n++
+		}
+	case a.avg:
+		if class, rv := sortClass(v); class == sortNumber {
+			a.sum += numberFloat(rv)
+			a.n++
+		}
+	default:
+		a.values = append(a.values, v)
+	}
+}
+
+func (a *runningAggregate) value() interface{} {
+	switch {
+	case a.merge:
+		return a.result
+	case a.count:
+		return a.n
+	case a.avg:
+		if a.n == 0 {
+			return nil
+		}
+		return a.sum / float64(a.n)
+	}
+	return a.f(a.values)
+}
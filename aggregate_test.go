@@ -0,0 +1,101 @@
+package cliview
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestAggregates(t *testing.T) {
+	values := []interface{}{3, nil, 10, 2}
+	if v := AggregateSum(values); v != 15 {
+		t.Errorf("Unexpected sum %v", v)
+	}
+	if v := AggregateAvg(values); v != 5.0 {
+		t.Errorf("Unexpected avg %v", v)
+	}
+	if v := AggregateMin(values); v != 2 {
+		t.Errorf("Unexpected min %v", v)
+	}
+	if v := AggregateMax(values); v != 10 {
+		t.Errorf("Unexpected max %v", v)
+	}
+	if v := AggregateCount(values); v != 3 {
+		t.Errorf("Unexpected count %v", v)
+	}
+	if v := AggregateSum([]interface{}{1, 0.5}); v != 1.5 {
+		t.Errorf("Unexpected sum %v", v)
+	}
+	if v := AggregateSum([]interface{}{time.Second, time.Minute}); v != 61*time.Second {
+		t.Errorf("Unexpected sum %v", v)
+	}
+	if v := AggregateSum([]interface{}{"a", nil}); v != nil {
+		t.Errorf("Unexpected sum %v", v)
+	}
+}
+
+func TestRunningAggregates(t *testing.T) {
+	custom := func(values []interface{}) interface{} { return len(values) }
+	for _, values := range [][]interface{}{
+		{3, nil, 10, 2},
+		{1, 0.5, "a"},
+		{time.Second, time.Minute},
+		{nil},
+		{},
+	} {
+		for _, f := range []AggregateFunc{AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount, custom} {
+			a := newRunningAggregate(f)
+			for _, v := range values {
+				a.add(v)
+			}
+			if v, expected := a.value(), f(values); v != expected {
+				t.Errorf("Running aggregate of %v is %v instead of %v", values, v, expected)
+			}
+		}
+	}
+	if a := newRunningAggregate(AggregateSum); !a.merge {
+		t.Errorf("AggregateSum doesn't keep a running sum")
+	}
+}
+
+func TestTableFooter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer: buf,
+			Styler: func(class, text string, data interface{}) string {
+				if class == "table:foot:cost" {
+					return "<" + text + ">"
+				}
+				return text
+			},
+		},
+		Columns: []Column{
+			Column{Title: "Item", Field: "item",
+				Aggregate: func(values []interface{}) interface{} {
+					return "Total"
+				},
+			},
+			Column{Title: "Cost", Field: "cost", Align: AlignRight, Aggregate: AggregateSum},
+			Column{Title: "Qty", Field: "qty", Aggregate: AggregateMax},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"item": "cpu", "cost": 1000, "qty": 2},
+		map[string]interface{}{"item": "ram", "cost": 250, "qty": 8},
+	})
+	result := buf.String()
+	if result != ""+
+		"+-----+----+---+\n"+
+		"|Item |Cost|Qty|\n"+
+		"+-----+----+---+\n"+
+		"|cpu  |1000|2  |\n"+
+		"+-----+----+---+\n"+
+		"|ram  | 250|8  |\n"+
+		"+-----+----+---+\n"+
+		"|Total|<1250>|8  |\n"+
+		"+-----+----+---+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
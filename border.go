@@ -0,0 +1,181 @@
+package cliview
+
+import (
+	"fmt"
+)
+
+// BorderStyle is the typed form of the Table.Border rune string. A zero
+// rune omits the character. A line (top, bottom or splitter) is not printed
+// if its fill rune is zero, or if its left rune is zero while the rows have
+// a left border.
+type BorderStyle struct {
+	TopLeft, Top, TopJunction, TopRight             rune // top: LT T TS RT
+	Left, Separator, Right                          rune // row: L S R
+	BottomLeft, Bottom, BottomJunction, BottomRight rune // bot: LB B BS RB
+
+	RowSplitLeft, RowSplit, RowSplitCross, RowSplitRight rune // row-splitter: LS C CS RS
+
+	HeadLeft, HeadSeparator, HeadRight rune // head: LH SH RH
+
+	HeadSplitLeft, HeadSplit, HeadSplitCross, HeadSplitRight rune // head-splitter: LSH CH CSH RSH
+
+	// junctions in splitters where only the separator below (Down) or above
+	// (Up) continues, used around spanning cells
+	RowSplitDown, RowSplitUp   rune // CD CU
+	HeadSplitDown, HeadSplitUp rune // CDH CUH
+}
+
+// Runes returns the border in the rune order of Table.Border.
+func (b BorderStyle) Runes() []rune {
+	return []rune{
+		b.TopLeft, b.Top, b.TopJunction, b.TopRight,
+		b.Left, b.Separator, b.Right,
+		b.BottomLeft, b.Bottom, b.BottomJunction, b.BottomRight,
+		b.RowSplitLeft, b.RowSplit, b.RowSplitCross, b.RowSplitRight,
+		b.HeadLeft, b.HeadSeparator, b.HeadRight,
+		b.HeadSplitLeft, b.HeadSplit, b.HeadSplitCross, b.HeadSplitRight,
+		b.RowSplitDown, b.RowSplitUp,
+		b.HeadSplitDown, b.HeadSplitUp,
+	}
+}
+
+// String returns the border string to be used as Table.Border.
+func (b BorderStyle) String() string {
+	return string(b.Runes())
+}
+
+// ParseBorder converts a Table.Border string to a BorderStyle. The string
+// must have 11, 15, 18, 22, 24 or 26 runes, see borderStyle for the parts
+// which can be left out.
+func ParseBorder(border string) (BorderStyle, error) {
+	chars := []rune(border)
+	switch len(chars) {
+	case 11, 15, 18, 22, 24, 26:
+		return borderStyle(chars), nil
+	}
+	return BorderStyle{}, fmt.Errorf("cliview: malformed border %q: %d runes instead of 11, 15, 18, 22, 24 or 26", border, len(chars))
+}
+
+// borderStyle converts at least 11 border runes to a BorderStyle. Without
+// the row-splitter there are no splitters, without the head runes the row
+// runes are used, without the head-splitter the row-splitter and without
+// the junctions the cross runes. Extra runes of an incomplete part are
+// ignored.
+func borderStyle(chars []rune) BorderStyle {
+	full := make([]rune, 26)
+	copy(full, chars)
+	if len(chars) < 15 {
+		copy(full[11:15], []rune{0, 0, 0, 0})
+	}
+	if len(chars) < 18 {
+		copy(full[15:18], full[4:7])
+	}
+	if len(chars) < 22 {
+		copy(full[18:22], full[11:15])
+	}
+	if len(chars) < 24 {
+		full[22], full[23] = full[13], full[13]
+	}
+	if len(chars) < 26 {
+		full[24], full[25] = full[20], full[20]
+	}
+	return BorderStyle{
+		full[0], full[1], full[2], full[3],
+		full[4], full[5], full[6],
+		full[7], full[8], full[9], full[10],
+		full[11], full[12], full[13], full[14],
+		full[15], full[16], full[17],
+		full[18], full[19], full[20], full[21],
+		full[22], full[23],
+		full[24], full[25],
+	}
+}
+
+var (
+	// BorderASCII uses only ASCII characters.
+	BorderASCII = BorderStyle{
+		'+', '-', '+', '+',
+		'|', '|', '|',
+		'+', '-', '+', '+',
+		'+', '-', '+', '+',
+		'|', '|', '|',
+		'+', '=', '+', '+',
+		'+', '+',
+		'+', '+',
+	}.String()
+
+	// BorderSingle uses single-line box drawing characters.
+	BorderSingle = BorderStyle{
+		'┌', '─', '┬', '┐',
+		'│', '│', '│',
+		'└', '─', '┴', '┘',
+		'├', '─', '┼', '┤',
+		'│', '│', '│',
+		'├', '─', '┼', '┤',
+		'┬', '┴',
+		'┬', '┴',
+	}.String()
+
+	// BorderRounded is BorderSingle with rounded corners.
+	BorderRounded = BorderStyle{
+		'╭', '─', '┬', '╮',
+		'│', '│', '│',
+		'╰', '─', '┴', '╯',
+		'├', '─', '┼', '┤',
+		'│', '│', '│',
+		'├', '─', '┼', '┤',
+		'┬', '┴',
+		'┬', '┴',
+	}.String()
+
+	// BorderHeavy uses heavy box drawing characters.
+	BorderHeavy = BorderStyle{
+		'┏', '━', '┳', '┓',
+		'┃', '┃', '┃',
+		'┗', '━', '┻', '┛',
+		'┣', '━', '╋', '┫',
+		'┃', '┃', '┃',
+		'┣', '━', '╋', '┫',
+		'┳', '┻',
+		'┳', '┻',
+	}.String()
+
+	// BorderMarkdown looks like a Markdown table: pipes between columns and
+	// a dashed line under the header only.
+	BorderMarkdown = BorderStyle{
+		0, 0, 0, 0,
+		'|', '|', '|',
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+		'|', '|', '|',
+		'|', '-', '|', '|',
+		0, 0,
+		'|', '|',
+	}.String()
+
+	// BorderHeader has no borders but a line under the header, columns are
+	// separated by a space.
+	BorderHeader = BorderStyle{
+		0, 0, 0, 0,
+		0, ' ', 0,
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+		0, ' ', 0,
+		0, '─', ' ', 0,
+		0, 0,
+		' ', ' ',
+	}.String()
+
+	// BorderPlain has no lines at all, columns are separated by a space like
+	// the output of kubectl.
+	BorderPlain = BorderStyle{
+		0, 0, 0, 0,
+		0, ' ', 0,
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+		0, ' ', 0,
+		0, 0, 0, 0,
+		0, 0,
+		0, 0,
+	}.String()
+)
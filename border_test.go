@@ -0,0 +1,107 @@
+package cliview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseBorder(t *testing.T) {
+	for _, border := range []string{BorderDouble, BorderDoubleCompact, BorderASCII, BorderSingle,
+		BorderRounded, BorderHeavy, BorderMarkdown, BorderHeader, BorderPlain} {
+		style, err := ParseBorder(border)
+		if err != nil {
+			t.Errorf("ParseBorder(%q) failed: %v", border, err)
+		} else if style.String() != border {
+			t.Errorf("ParseBorder(%q).String() = %q", border, style.String())
+		}
+	}
+
+	if len([]rune(BorderFull)) != 22 || len([]rune(BorderCompact)) != 22 {
+		t.Errorf("BorderFull and BorderCompact must keep their 22 runes")
+	}
+
+	style, err := ParseBorder(TestBorder)
+	if err != nil {
+		t.Fatalf("ParseBorder(%q) failed: %v", TestBorder, err)
+	}
+	if expected := TestBorder + "++++"; style.String() != expected {
+		t.Errorf("Unexpected junctions %q, expected %q", style.String(), expected)
+	}
+
+	style, err = ParseBorder("abcdefghijk")
+	if err != nil {
+		t.Fatalf("ParseBorder failed: %v", err)
+	}
+	if style.HeadLeft != 'e' || style.HeadSeparator != 'f' || style.HeadRight != 'g' {
+		t.Errorf("Head runes not taken from row: %q", style.String())
+	}
+	if style.RowSplit != 0 || style.HeadSplit != 0 {
+		t.Errorf("Unexpected splitters: %q", style.String())
+	}
+
+	for _, border := range []string{"", "+-+", TestBorder + "v"} {
+		if _, err := ParseBorder(border); err == nil {
+			t.Errorf("ParseBorder(%q) should fail", border)
+		}
+	}
+}
+
+func printBorder(border string) string {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Size", Field: "size", Align: AlignRight},
+		},
+		Border: border,
+	}
+	tv.Print([]map[string]interface{}{
+		{"name": "foo", "size": 1},
+		{"name": "bar", "size": 20},
+	})
+	return buf.String()
+}
+
+func TestBorderPresets(t *testing.T) {
+	tests := []struct {
+		border string
+		result string
+	}{
+		{BorderASCII, "" +
+			"+----+----+\n" +
+			"|Name|Size|\n" +
+			"+====+====+\n" +
+			"|foo |   1|\n" +
+			"+----+----+\n" +
+			"|bar |  20|\n" +
+			"+----+----+\n"},
+		{BorderRounded, "" +
+			"╭────┬────╮\n" +
+			"│Name│Size│\n" +
+			"├────┼────┤\n" +
+			"│foo │   1│\n" +
+			"├────┼────┤\n" +
+			"│bar │  20│\n" +
+			"╰────┴────╯\n"},
+		{BorderMarkdown, "" +
+			"|Name|Size|\n" +
+			"|----|----|\n" +
+			"|foo |   1|\n" +
+			"|bar |  20|\n"},
+		{BorderHeader, "" +
+			"Name Size\n" +
+			"──── ────\n" +
+			"foo     1\n" +
+			"bar    20\n"},
+		{BorderPlain, "" +
+			"Name Size\n" +
+			"foo     1\n" +
+			"bar    20\n"},
+	}
+	for _, test := range tests {
+		if result := printBorder(test.border); result != test.result {
+			t.Errorf("Unexpected output for %q\n%v", test.border, result)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cliview
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Color modes of Output.ColorMode
+const (
+	ColorModeAuto   = 0 // colors if writing to a terminal, following the environment
+	ColorModeAlways = 1
+	ColorModeNever  = 2
+)
+
+// color levels supported by the output
+const (
+	colorsNone = iota
+	colors16
+	colors256
+	colorsRGB
+)
+
+func (o *Output) getenv(key string) string {
+	if o.Getenv != nil {
+		return o.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+// colorLevel decides which colors can be written to w. In ColorModeAuto
+// colors are disabled by NO_COLOR, or if w is not a terminal or TERM is
+// "dumb" unless CLICOLOR_FORCE is set. The level is taken from COLORTERM
+// ("truecolor" or "24bit") and TERM ("*256color*").
+func (o *Output) colorLevel(w io.Writer) int {
+	switch o.ColorMode {
+	case ColorModeNever:
+		return colorsNone
+	case ColorModeAuto:
+		if o.getenv("NO_COLOR") != "" {
+			return colorsNone
+		}
+		if force := o.getenv("CLICOLOR_FORCE"); force == "" || force == "0" {
+			if _, isTerm := o.terminal(w); !isTerm || o.getenv("TERM") == "dumb" {
+				return colorsNone
+			}
+		}
+	}
+	switch colorTerm := o.getenv("COLORTERM"); {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return colorsRGB
+	case strings.Contains(o.getenv("TERM"), "256color"):
+		return colors256
+	}
+	return colors16
+}
+
+// colorCache is the color level of a writer in a color mode.
+type colorCache struct {
+	w     io.Writer
+	mode  int
+	level int
+}
+
+// cachedColorLevel returns the color level of Out, which is only decided
+// again if the writer or the ColorMode changes.
+func (o *Output) cachedColorLevel() int {
+	w := o.Out()
+	if c, ok := o.colors.Load().(*colorCache); ok && c.mode == o.ColorMode && sameWriter(c.w, w) {
+		return c.level
+	}
+	level := o.colorLevel(w)
+	o.colors.Store(&colorCache{w: w, mode: o.ColorMode, level: level})
+	return level
+}
+
+func sameWriter(a, b io.Writer) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// adaptColors converts the colors in the SGR sequences of text to the color
+// level, or removes the SGR sequences if colors are disabled.
+func adaptColors(text string, level int) string {
+	if level == colorsRGB || !strings.Contains(text, "\x1b[") {
+		return text
+	}
+	buf := new(strings.Builder)
+	for s := text; s != ""; {
+		i := strings.Index(s, "\x1b[")
+		if i < 0 {
+			buf.WriteString(s)
+			break
+		}
+		buf.WriteString(s[:i])
+		s = s[i:]
+		n := escapeLen(s)
+		if n <= 0 {
+			buf.WriteString(s)
+			break
+		}
+		if seq := s[:n]; seq[n-1] != 'm' {
+			buf.WriteString(seq)
+		} else if level != colorsNone {
+			buf.WriteString("\x1b[" + adaptSGR(seq[2:n-1], level) + "m")
+		}
+		s = s[n:]
+	}
+	return buf.String()
+}
+
+// adaptSGR converts the 256 and RGB colors in SGR parameters to the level.
+func adaptSGR(params string, level int) string {
+	ps := strings.Split(params, ";")
+	out := make([]string, 0, len(ps))
+	for i := 0; i < len(ps); i++ {
+		p := ps[i]
+		if (p != "38" && p != "48") || i+1 >= len(ps) {
+			out = append(out, p)
+			continue
+		}
+		bg := p == "48"
+		var r, g, b int
+		switch {
+		case ps[i+1] == "5" && i+2 < len(ps):
+			n := sgrByte(ps[i+2])
+			if level == colors256 {
+				out = append(out, ps[i:i+3]...)
+			} else {
+				r, g, b = rgbOf256(n)
+				out = append(out, (color16 + Color(nearest16(r, g, b))).sgr(bg))
+			}
+			i += 2
+		case ps[i+1] == "2" && i+4 < len(ps):
+			r, g, b = sgrByte(ps[i+2]), sgrByte(ps[i+3]), sgrByte(ps[i+4])
+			if level == colors256 {
+				out = append(out, Color256(uint8(nearest256(r, g, b))).sgr(bg))
+			} else {
+				out = append(out, (color16 + Color(nearest16(r, g, b))).sgr(bg))
+			}
+			i += 4
+		default:
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ";")
+}
+
+// sgrByte parses a color index or component clamped to 0-255.
+func sgrByte(param string) int {
+	n, _ := strconv.Atoi(param)
+	if n < 0 {
+		return 0
+	} else if n > 255 {
+		return 255
+	}
+	return n
+}
+
+// the 16 standard colors as displayed by xterm
+var palette16 = [16][3]int{
+	{0x00, 0x00, 0x00}, {0xcd, 0x00, 0x00}, {0x00, 0xcd, 0x00}, {0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee}, {0xcd, 0x00, 0xcd}, {0x00, 0xcd, 0xcd}, {0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// levels of the 6x6x6 color cube in the 256-color palette
+var cubeLevels = [6]int{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+
+func colorDistance(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+func rgbOf256(n int) (int, int, int) {
+	switch {
+	case n < 16:
+		c := palette16[n&15]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	gray := 8 + 10*(n-232)
+	return gray, gray, gray
+}
+
+func nearest16(r, g, b int) int {
+	best, dist := 0, -1
+	for i, c := range palette16 {
+		if d := colorDistance(r, g, b, c[0], c[1], c[2]); dist < 0 || d < dist {
+			best, dist = i, d
+		}
+	}
+	return best
+}
+
+func nearest256(r, g, b int) int {
+	cube := func(v int) int {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	ri, gi, bi := cube(r), cube(g), cube(b)
+	cr, cg, cb := cubeLevels[ri], cubeLevels[gi], cubeLevels[bi]
+
+	avg := (r + g + b) / 3
+	grayIndex := 23
+	if avg <= 238 {
+		if grayIndex = (avg - 3) / 10; grayIndex < 0 {
+			grayIndex = 0
+		}
+	}
+	gray := 8 + 10*grayIndex
+
+	if colorDistance(r, g, b, gray, gray, gray) < colorDistance(r, g, b, cr, cg, cb) {
+		return 232 + grayIndex
+	}
+	return 16 + 36*ri + 6*gi + bi
+}
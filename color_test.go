@@ -0,0 +1,124 @@
+package cliview
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestOutputColorLevel(t *testing.T) {
+	tests := []struct {
+		mode   int
+		isTerm bool
+		env    map[string]string
+		level  int
+	}{
+		{ColorModeAuto, true, nil, colors16},
+		{ColorModeAuto, false, nil, colorsNone},
+		{ColorModeAuto, true, map[string]string{"TERM": "xterm-256color"}, colors256},
+		{ColorModeAuto, true, map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, colorsRGB},
+		{ColorModeAuto, true, map[string]string{"COLORTERM": "24bit"}, colorsRGB},
+		{ColorModeAuto, true, map[string]string{"TERM": "dumb"}, colorsNone},
+		{ColorModeAuto, true, map[string]string{"NO_COLOR": "1"}, colorsNone},
+		{ColorModeAuto, false, map[string]string{"CLICOLOR_FORCE": "1", "TERM": "screen-256color"}, colors256},
+		{ColorModeAuto, false, map[string]string{"CLICOLOR_FORCE": "0"}, colorsNone},
+		{ColorModeAuto, false, map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, colorsNone},
+		{ColorModeAlways, false, map[string]string{"NO_COLOR": "1"}, colors16},
+		{ColorModeNever, true, map[string]string{"COLORTERM": "truecolor"}, colorsNone},
+	}
+	for i, test := range tests {
+		o := &Output{
+			ColorMode: test.mode,
+			Terminal:  func(w io.Writer) (int, bool) { return 80, test.isTerm },
+			Getenv:    func(key string) string { return test.env[key] },
+		}
+		if level := o.colorLevel(io.Discard); level != test.level {
+			t.Errorf("Unexpected level %d of test %d instead of %d", level, i, test.level)
+		}
+	}
+}
+
+func TestOutputStyling(t *testing.T) {
+	detected := 0
+	o := &Output{
+		Writer: io.Discard,
+		Styler: func(class, text string, data interface{}) string {
+			return "\x1b[1m" + text + "\x1b[0m"
+		},
+		Terminal: func(w io.Writer) (int, bool) {
+			detected++
+			return 80, false
+		},
+	}
+	for i := 0; i < 3; i++ {
+		if text := o.Styling("", "a", nil, nil); text != "a" {
+			t.Errorf("Unexpected text %q", text)
+		}
+	}
+	o.ColorMode = ColorModeAlways
+	if text := o.Styling("", "a", nil, nil); text != "\x1b[1ma\x1b[0m" {
+		t.Errorf("Unexpected text %q", text)
+	}
+	o.ColorMode = ColorModeAuto
+	o.Writer = new(bytes.Buffer)
+	o.Styling("", "a", nil, nil)
+	if detected != 2 {
+		t.Errorf("Terminal detected %d times instead of 2", detected)
+	}
+}
+
+func TestAdaptColors(t *testing.T) {
+	tests := []struct {
+		text   string
+		level  int
+		result string
+	}{
+		{"\x1b[1;38;2;255;128;0mx\x1b[0m", colorsRGB, "\x1b[1;38;2;255;128;0mx\x1b[0m"},
+		{"\x1b[1;38;2;255;128;0mx\x1b[0m", colors256, "\x1b[1;38;5;208mx\x1b[0m"},
+		{"\x1b[1;38;2;255;128;0mx\x1b[0m", colors16, "\x1b[1;33mx\x1b[0m"},
+		{"\x1b[48;2;40;40;40mx\x1b[0m", colors256, "\x1b[48;5;235mx\x1b[0m"},
+		{"\x1b[38;5;196;48;5;21mx\x1b[0m", colors256, "\x1b[38;5;196;48;5;21mx\x1b[0m"},
+		{"\x1b[38;5;196;48;5;21mx\x1b[0m", colors16, "\x1b[91;44mx\x1b[0m"},
+		{"\x1b[38;5;9mx", colors16, "\x1b[91mx"},
+		{"a\x1b[1;31mb\x1b[0mc", colorsNone, "abc"},
+		{"\x1b]8;;http://x\x1b\\\x1b[4mlink\x1b[0m\x1b]8;;\x1b\\", colorsNone, "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\"},
+		{"\x1b[2Kplain", colorsNone, "\x1b[2Kplain"},
+	}
+	for _, test := range tests {
+		if result := adaptColors(test.text, test.level); result != test.result {
+			t.Errorf("adaptColors(%q, %d) = %q instead of %q", test.text, test.level, result, test.result)
+		}
+	}
+}
+
+func TestTablePrintColors(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer:   buf,
+			Styler:   Theme{"table:head:": {Fg: RGB(255, 0, 0)}}.Styler(),
+			Terminal: func(w io.Writer) (int, bool) { return 80, true },
+			Getenv:   func(key string) string { return map[string]string{"TERM": "xterm-256color"}[key] },
+		},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{{"name": "foo"}})
+	if result := buf.String(); result != ""+
+		"+----+\n"+
+		"|\x1b[38;5;196mName\x1b[0m|\n"+
+		"+----+\n"+
+		"|foo |\n"+
+		"+----+\n" {
+		t.Errorf("Unexpected output\n%q", result)
+	}
+
+	tv.Getenv = func(key string) string { return map[string]string{"NO_COLOR": "1"}[key] }
+	tree := &Tree{Output: tv.Output, Indent: DefaultIndent}
+	tree.Styler = Theme{"tree:key:": {Bold: true}}.Styler()
+	if result := tree.Sprint(map[string]interface{}{"a": 1}); result != "a: 1\n" {
+		t.Errorf("Unexpected output\n%q", result)
+	}
+}
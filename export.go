@@ -0,0 +1,312 @@
+package cliview
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Machine-readable and markup output formats accepted by Table.PrintAs.
+const (
+	FormatTable     = "table"
+	FormatCSV       = "csv"
+	FormatTSV       = "tsv"
+	FormatJSON      = "json"
+	FormatJSONLines = "jsonl"
+	FormatMarkdown  = "markdown"
+	FormatHTML      = "html"
+)
+
+// PrintAs prints data in the named format, which is typically taken from a
+// command line option like "-o csv". An empty format prints the table.
+// It returns an error for an unknown format, data which can't be printed or
+// a failing write.
+func (tv *Table) PrintAs(format string, data interface{}) error {
+	rows, err := tv.rows(data)
+	if err != nil {
+		return err
+	}
+	w := tv.Out()
+	switch format = strings.ToLower(format); format {
+	case "", FormatTable:
+		_, err = tv.render(w, rows)
+		return err
+	}
+	rows, _ = tv.sortRows(rows, nil, nil)
+	switch format {
+	case FormatCSV:
+		return tv.writeCSV(w, rows)
+	case FormatTSV:
+		return tv.writeTSV(w, rows)
+	case FormatJSON:
+		return tv.writeJSON(w, rows)
+	case FormatJSONLines, "jsonlines", "ndjson":
+		return tv.writeJSONLines(w, rows)
+	case FormatMarkdown, "md":
+		return tv.writeMarkdown(w, rows)
+	case FormatHTML:
+		return tv.writeHTML(w, rows)
+	}
+	return fmt.Errorf("cliview: unknown output format %q", format)
+}
+
+// PrintCSV prints data as comma separated values with a header line of
+// column titles. Width, border and styling settings are ignored. It returns
+// an error if data can't be printed or writing fails, like PrintAs.
+func (tv *Table) PrintCSV(data interface{}) error {
+	return tv.PrintAs(FormatCSV, data)
+}
+
+// PrintTSV prints data as tab separated values with a header line of column
+// titles. Tabs, newlines and backslashes in values are escaped.
+func (tv *Table) PrintTSV(data interface{}) error {
+	return tv.PrintAs(FormatTSV, data)
+}
+
+// PrintJSON prints data as a JSON array of objects keyed by column fields.
+// Values keep their types, except in the columns with a Formatter, which get
+// the strings of the table: the formatter passed to Column.Formatter still
+// goes through Output.Formatter and Output.TypeFormatters.
+func (tv *Table) PrintJSON(data interface{}) error {
+	return tv.PrintAs(FormatJSON, data)
+}
+
+// PrintJSONLines prints one JSON object per line, keyed by column fields.
+func (tv *Table) PrintJSONLines(data interface{}) error {
+	return tv.PrintAs(FormatJSONLines, data)
+}
+
+// PrintMarkdown prints data as a GitHub-flavored Markdown table, alignment
+// markers are derived from Column.Align.
+func (tv *Table) PrintMarkdown(data interface{}) error {
+	return tv.PrintAs(FormatMarkdown, data)
+}
+
+// PrintHTML prints data as an HTML table. Cells get CSS classes derived from
+// the styling classes, e.g. "table:row:name" becomes "table-row-name".
+func (tv *Table) PrintHTML(data interface{}) error {
+	return tv.PrintAs(FormatHTML, data)
+}
+
+func (tv *Table) exportHeader(columns []Column) []string {
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.Title
+	}
+	return titles
+}
+
+func (tv *Table) exportRecord(columns []Column, row map[string]interface{}) []string {
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = tv.formatCell("table:row:", col, row)
+	}
+	return record
+}
+
+func (tv *Table) writeCSV(w io.Writer, rows []map[string]interface{}) error {
+	columns := tv.visibleColumns()
+	cw := csv.NewWriter(w)
+	cw.Write(tv.exportHeader(columns))
+	for _, row := range rows {
+		cw.Write(tv.exportRecord(columns, row))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+func (tv *Table) writeTSV(w io.Writer, rows []map[string]interface{}) error {
+	columns := tv.visibleColumns()
+	writeLine := func(fields []string) error {
+		for i, f := range fields {
+			fields[i] = tsvEscaper.Replace(f)
+		}
+		_, err := io.WriteString(w, strings.Join(fields, "\t")+"\n")
+		return err
+	}
+	if err := writeLine(tv.exportHeader(columns)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writeLine(tv.exportRecord(columns, row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalJSON is like json.Marshal without escaping HTML characters.
+func marshalJSON(v interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// jsonObject encodes a row as a JSON object with keys in column order. Values
+// keep their types unless the column has a Formatter, in which case the
+// strings formatted as in the table are used.
+func (tv *Table) jsonObject(columns []Column, row map[string]interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString("{")
+	for i, col := range columns {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key := col.Field
+		if key == "" {
+			key = col.Title
+		}
+		var val interface{}
+		if col.Formatter != nil {
+			val = tv.formatCell("table:row:", col, row)
+		} else {
+			val = tv.cellValue(col, row)
+		}
+		k, err := marshalJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshalJSON(val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+func (tv *Table) writeJSON(w io.Writer, rows []map[string]interface{}) error {
+	columns := tv.visibleColumns()
+	compact := new(bytes.Buffer)
+	compact.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			compact.WriteString(",")
+		}
+		obj, err := tv.jsonObject(columns, row)
+		if err != nil {
+			return err
+		}
+		compact.Write(obj)
+	}
+	compact.WriteString("]")
+	out := new(bytes.Buffer)
+	if err := json.Indent(out, compact.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteString("\n")
+	_, err := out.WriteTo(w)
+	return err
+}
+
+func (tv *Table) writeJSONLines(w io.Writer, rows []map[string]interface{}) error {
+	columns := tv.visibleColumns()
+	for _, row := range rows {
+		obj, err := tv.jsonObject(columns, row)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(append(obj, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func (tv *Table) writeMarkdown(w io.Writer, rows []map[string]interface{}) error {
+	columns := tv.visibleColumns()
+	writeLine := func(fields []string, escape bool) error {
+		buf := new(bytes.Buffer)
+		buf.WriteString("|")
+		for _, f := range fields {
+			if escape {
+				f = markdownEscaper.Replace(f)
+			}
+			buf.WriteString(" " + f + " |")
+		}
+		buf.WriteString("\n")
+		_, err := buf.WriteTo(w)
+		return err
+	}
+	if err := writeLine(tv.exportHeader(columns), true); err != nil {
+		return err
+	}
+	markers := make([]string, len(columns))
+	for i, col := range columns {
+		switch col.Align {
+		case AlignRight:
+			markers[i] = "---:"
+		case AlignMiddle:
+			markers[i] = ":---:"
+		default:
+			markers[i] = ":---"
+		}
+	}
+	if err := writeLine(markers, false); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writeLine(tv.exportRecord(columns, row), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cssClass converts a styling class to a CSS class name.
+func cssClass(class string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', ' ', '\t', '.', '#':
+			return '-'
+		}
+		return r
+	}, class)
+}
+
+func htmlCell(tag, class, text string, align int) string {
+	attrs := ` class="` + html.EscapeString(cssClass(class)) + `"`
+	switch align {
+	case AlignRight:
+		attrs += ` style="text-align: right"`
+	case AlignMiddle:
+		attrs += ` style="text-align: center"`
+	}
+	text = strings.Replace(html.EscapeString(text), "\n", "<br>", -1)
+	return "<" + tag + attrs + ">" + text + "</" + tag + ">"
+}
+
+func (tv *Table) writeHTML(w io.Writer, rows []map[string]interface{}) error {
+	columns := tv.visibleColumns()
+	buf := new(bytes.Buffer)
+	buf.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, col := range columns {
+		buf.WriteString(htmlCell("th", "table:head:"+col.Field, col.Title, col.Align))
+	}
+	buf.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+	for _, row := range rows {
+		buf.WriteString("    <tr>")
+		for _, col := range columns {
+			buf.WriteString(htmlCell("td", "table:row:"+col.Field, tv.formatCell("table:row:", col, row), col.Align))
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("  </tbody>\n</table>\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,228 @@
+package cliview
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+var exportTestData = []map[string]interface{}{
+	map[string]interface{}{"name": "Jack", "age": 30, "note": "say \"hi\", <b>"},
+	map[string]interface{}{"name": "Alice", "age": 7, "note": "a\tb\nc"},
+}
+
+func TestTablePrintCSV(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name", Width: 2},
+			Column{Title: "Age", Field: "age"},
+			Column{Title: "Note", Field: "note"},
+			Column{Title: "Double", Field: "double"},
+		},
+		Border: TestBorder,
+	}
+	tv.HideColumns("Double").PrintCSV(exportTestData)
+	result := buf.String()
+	if result != ""+
+		"Name,Age,Note\n"+
+		"Jack,30,\"say \"\"hi\"\", <b>\"\n"+
+		"Alice,7,\"a\tb\nc\"\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Writer = &failWriter{}
+	if err := tv.PrintCSV(exportTestData); err != io.ErrClosedPipe {
+		t.Errorf("PrintCSV returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTablePrintTSV(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age"},
+			Column{Title: "Note", Field: "note"},
+		},
+	}
+	tv.PrintTSV(exportTestData)
+	result := buf.String()
+	if result != ""+
+		"Name\tAge\tNote\n"+
+		"Jack\t30\tsay \"hi\", <b>\n"+
+		"Alice\t7\ta\\tb\\nc\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Writer = &failWriter{n: 20}
+	if err := tv.PrintTSV(exportTestData); err != io.ErrClosedPipe {
+		t.Errorf("PrintTSV returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTablePrintJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer: buf,
+			Formatter: func(class string, data interface{}, formatter FormatterFunc) string {
+				return "<" + formatter(class, data, nil) + ">"
+			},
+			TypeFormatters: NewTypeFormatters().Register(0, ThousandsFormatter(",")),
+		},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age"},
+			Column{Title: "Note", Field: "note"},
+			Column{Title: "Double", Field: "double",
+				Fetcher: func(col Column, row map[string]interface{}) interface{} {
+					return row["age"].(int) * 200
+				},
+			},
+			Column{Title: "Days", Field: "days",
+				Fetcher: func(col Column, row map[string]interface{}) interface{} {
+					return row["age"].(int) * 365
+				},
+				Formatter: func(class string, data interface{}, formatter FormatterFunc) string {
+					return formatter(class, data, nil) + "d"
+				},
+			},
+		},
+	}
+	tv.PrintJSON(exportTestData)
+	result := buf.String()
+	if result != ""+
+		"[\n"+
+		"  {\n"+
+		"    \"name\": \"Jack\",\n"+
+		"    \"age\": 30,\n"+
+		"    \"note\": \"say \\\"hi\\\", <b>\",\n"+
+		"    \"double\": 6000,\n"+
+		"    \"days\": \"<10,950>d\"\n"+
+		"  },\n"+
+		"  {\n"+
+		"    \"name\": \"Alice\",\n"+
+		"    \"age\": 7,\n"+
+		"    \"note\": \"a\\tb\\nc\",\n"+
+		"    \"double\": 1400,\n"+
+		"    \"days\": \"<2,555>d\"\n"+
+		"  }\n"+
+		"]\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Writer = &failWriter{}
+	if err := tv.PrintJSON(exportTestData); err != io.ErrClosedPipe {
+		t.Errorf("PrintJSON returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTablePrintJSONLines(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age",
+				Formatter: func(class string, data interface{}, formatter FormatterFunc) string {
+					return formatter(class, data, nil) + "y"
+				},
+			},
+			Column{Title: "Note", Field: "note"},
+		},
+	}
+	tv.PrintJSONLines(exportTestData)
+	result := buf.String()
+	if result != ""+
+		"{\"name\":\"Jack\",\"age\":\"30y\",\"note\":\"say \\\"hi\\\", <b>\"}\n"+
+		"{\"name\":\"Alice\",\"age\":\"7y\",\"note\":\"a\\tb\\nc\"}\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Writer = &failWriter{n: 10}
+	if err := tv.PrintJSONLines(exportTestData); err != io.ErrClosedPipe {
+		t.Errorf("PrintJSONLines returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTablePrintAs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age"},
+			Column{Title: "Note", Field: "note"},
+		},
+	}
+	if err := tv.PrintAs("CSV", exportTestData); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Name,Age,Note\n") {
+		t.Errorf("Unexpected output\n%v", buf.String())
+	}
+	if err := tv.PrintAs("yaml", exportTestData); err == nil {
+		t.Errorf("Expect error for unknown format")
+	}
+}
+
+func TestTablePrintMarkdown(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age", Align: AlignRight},
+			Column{Title: "Note", Field: "note", Align: AlignMiddle},
+		},
+	}
+	tv.PrintMarkdown([]map[string]interface{}{
+		map[string]interface{}{"name": "a|b", "age": 1, "note": "x\ny"},
+	})
+	result := buf.String()
+	if result != ""+
+		"| Name | Age | Note |\n"+
+		"| :--- | ---: | :---: |\n"+
+		"| a\\|b | 1 | x<br>y |\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Writer = &failWriter{}
+	if err := tv.PrintMarkdown(exportTestData); err != io.ErrClosedPipe {
+		t.Errorf("PrintMarkdown returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTablePrintHTML(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age", Align: AlignRight},
+			Column{Title: "Note", Field: "note"},
+		},
+	}
+	tv.PrintHTML(exportTestData[:1])
+	result := buf.String()
+	if result != ""+
+		"<table>\n"+
+		"  <thead>\n"+
+		"    <tr><th class=\"table-head-name\">Name</th><th class=\"table-head-age\" style=\"text-align: right\">Age</th><th class=\"table-head-note\">Note</th></tr>\n"+
+		"  </thead>\n"+
+		"  <tbody>\n"+
+		"    <tr><td class=\"table-row-name\">Jack</td><td class=\"table-row-age\" style=\"text-align: right\">30</td><td class=\"table-row-note\">say &#34;hi&#34;, &lt;b&gt;</td></tr>\n"+
+		"  </tbody>\n"+
+		"</table>\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Writer = &failWriter{}
+	if err := tv.PrintHTML(exportTestData); err != io.ErrClosedPipe {
+		t.Errorf("PrintHTML returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
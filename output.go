@@ -5,16 +5,56 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync/atomic"
 )
 
 type StylerFunc func(class, text string, data interface{}) string
 type FormatterFunc func(class string, data interface{}, formatter FormatterFunc) string
 
+// Output holds the output settings shared by Table and Tree. It is only read
+// while printing, so the functions set in it must be safe for concurrent use
+// if the views are printed concurrently.
 type Output struct {
 	Padding   int
 	Writer    io.Writer
 	Styler    StylerFunc
 	Formatter FormatterFunc
+	Terminal  TerminalFunc // detects terminal output, DetectTerminal if nil
+
+	// DefaultWidth is the width used when the output is not a terminal, or
+	// the terminal width is unknown and $COLUMNS isn't set. 0 doesn't limit
+	// the width.
+	DefaultWidth int
+
+	// ColorMode decides if the ANSI colors and attributes written by stylers
+	// are kept: ColorModeAuto keeps them for terminals unless disabled by the
+	// environment, converting colors the terminal doesn't support.
+	ColorMode int
+	Getenv    func(key string) string // environment lookup, os.Getenv if nil
+
+	// TypeFormatters formats values by their type, as the formatter passed
+	// to Formatter or instead of it.
+	TypeFormatters *TypeFormatters
+
+	colors atomic.Value // *colorCache of Styling
+}
+
+// errWriter counts the bytes written to w and keeps the first error, after
+// which nothing is written.
+type errWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.n += int64(n)
+	ew.err = err
+	return n, err
 }
 
 func PaddingBuffer(padding int) *bytes.Buffer {
@@ -44,12 +84,21 @@ func (o *Output) Out() io.Writer {
 	return os.Stdout
 }
 
+// Styling styles text with styler, or Output.Styler if nil, adapting the
+// ANSI escape sequences to the ColorMode. The color level is decided once
+// for the writer and ColorMode, later changes of the environment are not
+// taken into account.
 func (o *Output) Styling(class, text string, data interface{}, styler StylerFunc) string {
+	return o.style(o.cachedColorLevel(), class, text, data, styler)
+}
+
+// style is Styling with the color level decided once per print.
+func (o *Output) style(colors int, class, text string, data interface{}, styler StylerFunc) string {
 	if styler == nil {
 		styler = o.Styler
 	}
 	if styler != nil {
-		return styler(class, text, data)
+		return adaptColors(styler(class, text, data), colors)
 	}
 	return text
 }
@@ -60,15 +109,19 @@ func defaultFormatter(class string, data interface{}, formatter FormatterFunc) s
 	}
 	switch data.(type) {
 	case float32, float64:
-		return fmt.Sprintf("%g", data.(float64))
+		return fmt.Sprintf("%g", data)
 	}
 	return fmt.Sprintf("%v", data)
 }
 
 func (o *Output) Format(class string, data interface{}) string {
+	formatter := defaultFormatter
+	if o.TypeFormatters != nil {
+		formatter = o.TypeFormatters.Format
+	}
 	if o.Formatter != nil {
-		return o.Formatter(class, data, defaultFormatter)
+		return o.Formatter(class, data, formatter)
 	} else {
-		return defaultFormatter(class, data, nil)
+		return formatter(class, data, nil)
 	}
 }
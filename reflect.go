@@ -0,0 +1,207 @@
+package cliview
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type fieldInfo struct {
+	key       string   // preferred name: cliview tag, json tag or Go field name
+	names     []string // all names the field can be looked up with
+	index     []int
+	depth     int
+	omitEmpty bool
+	hidden    bool // excluded from keyed views (tagged "-")
+}
+
+func parseTag(tag string) (name string, omitEmpty bool) {
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty
+}
+
+// collectFields appends the fields of t, promoting the fields of embedded
+// structs. path holds the types being embedded, repeats are skipped to
+// stop at embedding cycles.
+func collectFields(t reflect.Type, index []int, path []reflect.Type, fields []fieldInfo) []fieldInfo {
+	for _, p := range path {
+		if p == t {
+			return fields
+		}
+	}
+	path = append(path[:len(path):len(path)], t)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		cvName, cvOmit := parseTag(sf.Tag.Get("cliview"))
+		jsName, jsOmit := parseTag(sf.Tag.Get("json"))
+		if cvName == "-" {
+			continue
+		}
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && ft.Kind() == reflect.Struct && cvName == "" && jsName == "" {
+			fields = collectFields(ft, idx, path, fields)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		info := fieldInfo{
+			key:       sf.Name,
+			names:     []string{sf.Name},
+			index:     idx,
+			depth:     len(index),
+			omitEmpty: cvOmit || jsOmit,
+		}
+		if jsName == "-" {
+			info.hidden = cvName == ""
+		} else if jsName != "" {
+			info.key = jsName
+			info.names = append(info.names, jsName)
+		}
+		if cvName != "" {
+			info.key = cvName
+			info.names = append(info.names, cvName)
+		}
+		fields = append(fields, info)
+	}
+	return fields
+}
+
+// fieldCache holds the []fieldInfo of the struct types resolved so far.
+var fieldCache sync.Map
+
+// structFields lists the exported fields of a struct type, including the
+// ones promoted from embedded structs. Shallower fields shadow deeper ones.
+// The fields are resolved once per type, the result must not be modified.
+func structFields(t reflect.Type) []fieldInfo {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]fieldInfo)
+	}
+	fields, _ := fieldCache.LoadOrStore(t, resolveFields(t))
+	return fields.([]fieldInfo)
+}
+
+func resolveFields(t reflect.Type) []fieldInfo {
+	all := collectFields(t, nil, nil, nil)
+	depths := make(map[string]int)
+	for _, f := range all {
+		if d, exist := depths[f.key]; !exist || f.depth < d {
+			depths[f.key] = f.depth
+		}
+	}
+	fields := make([]fieldInfo, 0, len(all))
+	for _, f := range all {
+		if depths[f.key] == f.depth {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// fieldValue is like reflect.Value.FieldByIndex but returns an invalid value
+// instead of panicking on nil embedded pointers.
+func fieldValue(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func rowFromValue(v reflect.Value) (map[string]interface{}, error) {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return map[string]interface{}{}, nil
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if m, ok := v.Interface().(map[string]interface{}); ok {
+			return m, nil
+		}
+		row := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			var key string
+			if k.Kind() == reflect.String {
+				key = k.String()
+			} else {
+				key = fmt.Sprintf("%v", k.Interface())
+			}
+			row[key] = valueInterface(v.MapIndex(k))
+		}
+		return row, nil
+	case reflect.Struct:
+		fields := structFields(v.Type())
+		row := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			val := valueInterface(fieldValue(v, f.index))
+			for _, name := range f.names {
+				row[name] = val
+			}
+		}
+		return row, nil
+	}
+	return nil, fmt.Errorf("cliview: unsupported table row type %s", v.Type())
+}
+
+// tableRows converts the data accepted by Table into rows keyed by field
+// names. data can be a slice or array of maps, structs or pointers to them.
+func tableRows(data interface{}) ([]map[string]interface{}, error) {
+	switch rows := data.(type) {
+	case nil:
+		return nil, nil
+	case []map[string]interface{}:
+		return rows, nil
+	}
+	v := indirectValue(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cliview: table data must be a slice, got %s", v.Type())
+	}
+	rows := make([]map[string]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		row, err := rowFromValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
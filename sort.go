@@ -0,0 +1,283 @@
+package cliview
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey sorts table rows by the value of a column field.
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSortKeys parses a comma separated list of fields like "name,-age".
+// A field prefixed with "-" sorts in descending order, "+" or no prefix in
+// ascending order.
+func ParseSortKeys(spec string) []SortKey {
+	keys := make([]SortKey, 0)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		key := SortKey{}
+		if strings.HasPrefix(field, "-") {
+			key.Descending = true
+			field = field[1:]
+		} else if strings.HasPrefix(field, "+") {
+			field = field[1:]
+		}
+		if key.Field = strings.TrimSpace(field); key.Field != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// value classes in sort order for values of different kinds
+const (
+	sortNil = iota
+	sortBool
+	sortNumber
+	sortTime
+	sortString
+	sortOther
+)
+
+func sortClass(v interface{}) (int, reflect.Value) {
+	if v == nil {
+		return sortNil, reflect.Value{}
+	}
+	if _, ok := v.(time.Time); ok {
+		return sortTime, reflect.Value{}
+	}
+	rv := indirectValue(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return sortNil, rv
+	case reflect.Bool:
+		return sortBool, rv
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return sortNumber, rv
+	case reflect.String:
+		return sortString, rv
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		return sortTime, reflect.ValueOf(t)
+	}
+	return sortOther, rv
+}
+
+func compareNumbers(a, b reflect.Value) int {
+	switch {
+	case isIntKind(a.Kind()) && isIntKind(b.Kind()):
+		return compareInts(a.Int(), b.Int())
+	case isUintKind(a.Kind()) && isUintKind(b.Kind()):
+		x, y := a.Uint(), b.Uint()
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	}
+	x, y := numberFloat(a), numberFloat(b)
+	if x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+	return 0
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func numberFloat(v reflect.Value) float64 {
+	switch {
+	case isIntKind(v.Kind()):
+		return float64(v.Int())
+	case isUintKind(v.Kind()):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+func compareInts(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// nextChunk splits s into a leading run of digits or non-digits and the rest.
+func nextChunk(s string) (string, string) {
+	i := 1
+	for digit := isDigit(s[0]); i < len(s) && isDigit(s[i]) == digit; i++ {
+	}
+	return s[:i], s[i:]
+}
+
+// CompareNatural compares strings in natural order, where runs of digits
+// are compared by their numeric value, e.g. "file2" sorts before "file10".
+func CompareNatural(a, b string) int {
+	x, y := a, b
+	for x != "" && y != "" {
+		var cx, cy string
+		cx, x = nextChunk(x)
+		cy, y = nextChunk(y)
+		if isDigit(cx[0]) && isDigit(cy[0]) {
+			nx, ny := strings.TrimLeft(cx, "0"), strings.TrimLeft(cy, "0")
+			if len(nx) != len(ny) {
+				return compareInts(int64(len(nx)), int64(len(ny)))
+			}
+			if c := strings.Compare(nx, ny); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(cx, cy); c != 0 {
+			return c
+		}
+	}
+	if x != "" || y != "" {
+		return compareInts(int64(len(x)), int64(len(y)))
+	}
+	return strings.Compare(a, b)
+}
+
+// CompareValues compares two values for sorting: numbers numerically, times
+// chronologically, strings in natural order and nil before anything else.
+// Values of different kinds are ordered by kind.
+func CompareValues(a, b interface{}) int {
+	ca, va := sortClass(a)
+	cb, vb := sortClass(b)
+	if ca != cb {
+		return compareInts(int64(ca), int64(cb))
+	}
+	switch ca {
+	case sortNil:
+		return 0
+	case sortBool:
+		if va.Bool() == vb.Bool() {
+			return 0
+		} else if vb.Bool() {
+			return -1
+		}
+		return 1
+	case sortNumber:
+		return compareNumbers(va, vb)
+	case sortTime:
+		ta, tb := timeOf(a, va), timeOf(b, vb)
+		if ta.Before(tb) {
+			return -1
+		} else if ta.After(tb) {
+			return 1
+		}
+		return 0
+	case sortString:
+		return CompareNatural(va.String(), vb.String())
+	}
+	return CompareNatural(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+func timeOf(v interface{}, rv reflect.Value) time.Time {
+	if t, ok := v.(time.Time); ok {
+		return t
+	}
+	return rv.Interface().(time.Time)
+}
+
+type rowSorter struct {
+	rows []map[string]interface{}
+	grid [][]tableCell
+	keys [][]interface{}
+	sort []SortKey
+}
+
+func (s *rowSorter) Len() int {
+	return len(s.rows)
+}
+
+func (s *rowSorter) Swap(i, j int) {
+	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+	if s.grid != nil {
+		s.grid[i], s.grid[j] = s.grid[j], s.grid[i]
+	}
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+func (s *rowSorter) Less(i, j int) bool {
+	for n, key := range s.sort {
+		c := CompareValues(s.keys[i][n], s.keys[j][n])
+		if key.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// sortRows returns copies of rows and of their grid of cells in columns,
+// stably sorted by SortBy. grid may be nil. The values of the sort fields
+// are taken from the grid, other values are fetched with the Fetcher of the
+// column having the sort field, if any.
+func (tv *Table) sortRows(rows []map[string]interface{}, columns []Column, grid [][]tableCell) ([]map[string]interface{}, [][]tableCell) {
+	if len(tv.SortBy) == 0 {
+		return rows, grid
+	}
+	s := &rowSorter{
+		rows: make([]map[string]interface{}, len(rows)),
+		keys: make([][]interface{}, len(rows)),
+		sort: tv.SortBy,
+	}
+	copy(s.rows, rows)
+	if grid != nil {
+		s.grid = make([][]tableCell, len(grid))
+		copy(s.grid, grid)
+	}
+	sortColumns := make([]Column, len(tv.SortBy))
+	gridIndex := make([]int, len(tv.SortBy))
+	for n, key := range tv.SortBy {
+		sortColumns[n] = Column{Field: key.Field}
+		for _, col := range tv.Columns {
+			if col.Field == key.Field {
+				sortColumns[n] = col
+				break
+			}
+		}
+		gridIndex[n] = -1
+		for i, col := range columns {
+			if grid != nil && col.Field == key.Field {
+				gridIndex[n] = i
+				break
+			}
+		}
+	}
+	for i, row := range s.rows {
+		s.keys[i] = make([]interface{}, len(sortColumns))
+		for n, col := range sortColumns {
+			if k := gridIndex[n]; k >= 0 && s.grid[i][k].span != 0 {
+				s.keys[i][n] = s.grid[i][k].value
+			} else {
+				s.keys[i][n] = tv.cellValue(col, row)
+			}
+		}
+	}
+	sort.Stable(s)
+	return s.rows, s.grid
+}
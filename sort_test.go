@@ -0,0 +1,102 @@
+package cliview
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	keys := ParseSortKeys("name, -age,+id,,")
+	expect := []SortKey{
+		SortKey{Field: "name"},
+		SortKey{Field: "age", Descending: true},
+		SortKey{Field: "id"},
+	}
+	if !reflect.DeepEqual(keys, expect) {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	cases := []struct {
+		a, b   string
+		expect int
+	}{
+		{"file2", "file10", -1},
+		{"file10", "file2", 1},
+		{"a01", "a1", -1},
+		{"a1b", "a1b", 0},
+		{"abc", "abd", -1},
+		{"v1.10.0", "v1.9.3", 1},
+		{"x", "x1", -1},
+	}
+	for _, c := range cases {
+		if r := CompareNatural(c.a, c.b); r != c.expect {
+			t.Errorf("CompareNatural(%q, %q) = %d, expect %d", c.a, c.b, r, c.expect)
+		}
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		a, b   interface{}
+		expect int
+	}{
+		{nil, 1, -1},
+		{2, 10, -1},
+		{int64(3), 2.5, 1},
+		{uint(7), 7, 0},
+		{false, true, -1},
+		{now, now.Add(time.Second), -1},
+		{&now, now, 0},
+		{"n9", "n10", -1},
+		{1, "1", -1},
+	}
+	for _, c := range cases {
+		if r := CompareValues(c.a, c.b); r != c.expect {
+			t.Errorf("CompareValues(%v, %v) = %d, expect %d", c.a, c.b, r, c.expect)
+		}
+	}
+}
+
+func TestTableSortBy(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Age", Field: "age",
+				Fetcher: func(col Column, row map[string]interface{}) interface{} {
+					return row["born"].(int) * -1
+				},
+			},
+		},
+		SortBy: ParseSortKeys("-age,name"),
+		Border: TestBorder,
+	}
+	data := []map[string]interface{}{
+		map[string]interface{}{"name": "n10", "born": -5},
+		map[string]interface{}{"name": "n9", "born": -5},
+		map[string]interface{}{"name": "a", "born": -30},
+	}
+	tv.Print(data)
+	result := buf.String()
+	if result != ""+
+		"+----+---+\n"+
+		"|Name|Age|\n"+
+		"+----+---+\n"+
+		"|a   |30 |\n"+
+		"+----+---+\n"+
+		"|n9  |5  |\n"+
+		"+----+---+\n"+
+		"|n10 |5  |\n"+
+		"+----+---+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	if data[0]["name"] != "n10" {
+		t.Errorf("Input data modified")
+	}
+}
@@ -0,0 +1,163 @@
+package cliview
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DefaultSampleRows is the number of rows sampled by Table.Stream if
+// Table.SampleRows is not set.
+const DefaultSampleRows = 100
+
+// TableStream prints a table row by row, see Table.Stream. It must not be
+// used from multiple goroutines concurrently.
+type TableStream struct {
+	r       *tableRender
+	size    int                 // number of rows to sample
+	sample  [][]tableCell       // rows not printed yet
+	totals  []*runningAggregate // column aggregates for the footer
+	started bool                // the head is printed
+	skip    bool                // the columns have no width
+	closed  bool
+}
+
+// Stream starts printing a table whose rows are pushed one at a time, for
+// listings too long to be kept in memory. Auto column widths are decided
+// from the first SampleRows rows, which are printed then, later rows are
+// printed immediately and truncated (or wrapped) to these widths. Without
+// auto-width columns nothing is sampled. Close prints the footer and the
+// bottom border. SortBy, GroupBy and Layout don't apply to streams. Like
+// Print, the configuration is not validated. A write error is returned by
+// Push and Close, after which nothing is printed.
+func (tv *Table) Stream() *TableStream {
+	s := &TableStream{
+		r:    tv.newRender(tv.Out()),
+		size: tv.SampleRows,
+	}
+	if s.size <= 0 {
+		s.size = DefaultSampleRows
+	}
+	auto := false
+	for _, col := range s.r.columns {
+		auto = auto || col.Width == 0
+	}
+	if !auto {
+		s.size = 0
+	}
+	if hasAggregate(s.r.columns) {
+		s.totals = make([]*runningAggregate, len(s.r.columns))
+		for i, col := range s.r.columns {
+			if col.Aggregate != nil {
+				s.totals[i] = newRunningAggregate(col.Aggregate)
+			}
+		}
+	}
+	return s
+}
+
+// Push adds a row, which is a map with string keys, a struct or a pointer
+// to them.
+func (s *TableStream) Push(row interface{}) error {
+	if s.closed {
+		return fmt.Errorf("cliview: push to closed table stream")
+	}
+	if err := s.failure(); err != nil {
+		return err
+	}
+	data, err := rowFromValue(reflect.ValueOf(row))
+	if err != nil {
+		return err
+	}
+	cells := s.r.cells(data)
+	if s.started {
+		s.print(cells)
+	} else if s.sample = append(s.sample, cells); len(s.sample) >= s.size {
+		s.start()
+	}
+	return s.failure()
+}
+
+func (s *TableStream) failure() error {
+	return s.r.w.err
+}
+
+// Close prints the rows still sampled, the footer and the bottom border.
+func (s *TableStream) Close() error {
+	if s.closed || s.failure() != nil {
+		s.closed = true
+		return s.failure()
+	}
+	if !s.started {
+		s.start()
+	}
+	s.closed = true
+	if s.skip {
+		return s.failure()
+	}
+	r := s.r
+	if s.totals != nil {
+		vals := make([]interface{}, len(r.columns))
+		strs := make([]string, len(r.columns))
+		for i, col := range r.columns {
+			if s.totals[i] != nil {
+				vals[i] = s.totals[i].value()
+				strs[i] = r.view.formatValue("table:foot:"+col.Field, col, vals[i])
+			}
+		}
+		r.printTotals("foot", vals, strs, r.headSepOff, r.headRowOff)
+	}
+	r.printBottom()
+	return s.failure()
+}
+
+// start decides the column widths and prints the head and the sampled rows.
+func (s *TableStream) start() {
+	s.started = true
+	s.r.layout(s.sample, nil)
+	if s.skip = !s.r.printable(); s.skip {
+		return
+	}
+	s.r.printHead()
+	for _, cells := range s.sample {
+		s.print(cells)
+	}
+	s.sample = nil
+}
+
+func (s *TableStream) print(cells []tableCell) {
+	if s.skip {
+		return
+	}
+	for i, total := range s.totals {
+		if total != nil {
+			total.add(cells[i].value)
+		}
+	}
+	s.r.printData(cells)
+}
+
+// PrintChan prints the rows received from rows, a channel of any row type
+// (e.g. chan *Item or <-chan interface{}), as a stream until it is closed.
+// After a row which can't be printed, the rest is received but not printed
+// and the error is returned.
+func (tv *Table) PrintChan(rows interface{}) error {
+	ch := reflect.ValueOf(rows)
+	if ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 {
+		return fmt.Errorf("cliview: PrintChan needs a receivable channel, got %T", rows)
+	}
+	s := tv.Stream()
+	var err error
+	for {
+		row, ok := ch.Recv()
+		if !ok {
+			break
+		}
+		if err == nil {
+			err = s.Push(row.Interface())
+		}
+	}
+	if cerr := s.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
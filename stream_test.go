@@ -0,0 +1,117 @@
+package cliview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableStream(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "Name"},
+			Column{Title: "Size", Field: "Size", Align: AlignRight, Aggregate: AggregateSum},
+		},
+		Border:     TestBorder,
+		SampleRows: 2,
+	}
+	type item struct {
+		Name string
+		Size int
+	}
+	s := tv.Stream()
+	s.Push(item{"foo", 1})
+	if buf.Len() != 0 {
+		t.Errorf("Unexpected output before sampling\n%v", buf.String())
+	}
+	s.Push(&item{"bar", 20})
+	if result := buf.String(); result != ""+
+		"+----+----+\n"+
+		"|Name|Size|\n"+
+		"+----+----+\n"+
+		"|foo |   1|\n"+
+		"+----+----+\n"+
+		"|bar |  20|\n" {
+		t.Errorf("Unexpected output after sampling\n%v", result)
+	}
+	s.Push(map[string]interface{}{"Name": "longer", "Size": 300})
+	if err := s.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if err := s.Push(item{"baz", 1}); err == nil {
+		t.Errorf("Push after Close should fail")
+	}
+	if result := buf.String(); result != ""+
+		"+----+----+\n"+
+		"|Name|Size|\n"+
+		"+----+----+\n"+
+		"|foo |   1|\n"+
+		"+----+----+\n"+
+		"|bar |  20|\n"+
+		"+----+----+\n"+
+		"|l...| 300|\n"+
+		"+----+----+\n"+
+		"|    | 321|\n"+
+		"+----+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintChan(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name", Width: 5},
+		},
+		Border:     TestBorder,
+		SampleRows: 10,
+	}
+	rows := make(chan interface{})
+	go func() {
+		rows <- map[string]interface{}{"name": "foo"}
+		rows <- 42
+		rows <- map[string]interface{}{"name": "bar"}
+		close(rows)
+	}()
+	if err := tv.PrintChan(rows); err == nil {
+		t.Errorf("PrintChan should fail for an invalid row")
+	}
+	if result := buf.String(); result != ""+
+		"+-----+\n"+
+		"|Name |\n"+
+		"+-----+\n"+
+		"|foo  |\n"+
+		"+-----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	type item struct {
+		Name string `json:"name"`
+	}
+	items := make(chan *item, 2)
+	items <- &item{"baz"}
+	items <- &item{"qux"}
+	close(items)
+	buf.Reset()
+	if err := tv.PrintChan((<-chan *item)(items)); err != nil {
+		t.Errorf("PrintChan failed: %v", err)
+	}
+	if result := buf.String(); result != ""+
+		"+-----+\n"+
+		"|Name |\n"+
+		"+-----+\n"+
+		"|baz  |\n"+
+		"+-----+\n"+
+		"|qux  |\n"+
+		"+-----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	if err := tv.PrintChan([]*item{}); err == nil {
+		t.Errorf("PrintChan should fail for a slice")
+	}
+	if err := tv.PrintChan(make(chan<- *item)); err == nil {
+		t.Errorf("PrintChan should fail for a send-only channel")
+	}
+}
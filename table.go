@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"unicode/utf8"
 )
@@ -68,6 +69,11 @@ var (
 	}
 
 	BorderCompact = string(runesBorderCompact)
+
+	// BorderDouble and BorderDoubleCompact are BorderFull and BorderCompact
+	// with the junctions used around spanning cells.
+	BorderDouble        = BorderFull + "\u252c\u2534\u2564\u2567"
+	BorderDoubleCompact = BorderCompact + "\u252c\u2534\u2564\u2567"
 )
 
 type Column struct {
@@ -75,26 +81,73 @@ type Column struct {
 	Field     string // field name for retrieving data
 	Width     int    // column width, >0 fixed, =0 auto, <0 percentage
 	MaxWidth  int    // maximum column width
+	MinWidth  int    // minimum column width when shrinking to fit, defaultMinWidth if 0
+	Priority  int    // columns with lower priority are shrunk first
 	Align     int
+	Wrap      bool          // wrap text into multiple lines instead of truncating
+	Aggregate AggregateFunc // value shown in the footer, computed from the column values
 	Fetcher   func(column Column, row map[string]interface{}) interface{}
 	Formatter FormatterFunc
 	Styler    StylerFunc
 }
 
+// ColumnGroup is a header spanning adjacent columns.
+type ColumnGroup struct {
+	Title  string
+	Fields []string // fields of the spanned columns
+	Align  int
+	Styler StylerFunc
+}
+
+// Table prints data as a table. The rendering state is local to each call,
+// so a configured Table, e.g. a package-level var, can be printed from
+// multiple goroutines concurrently, as long as it isn't modified (including
+// HideColumns) at the same time. Concurrent prints to the same Writer
+// interleave their lines, copy the Table and set Output.Writer of the copy
+// to print to separate writers.
 type Table struct {
 	Output
 
-	Border string // border characters
+	Border string // border characters, see BorderStyle and the Border presets
 	// top: LT T TS RT
 	// row: L S R
 	// bot: LB B BS RB
 	// row-splitter: LS C CS RS
 	// head: LH SH RH
 	// head-splitter: LSH CH CSH RSH
-	Columns  []Column // Column definitions
-	MaxWidth int      // maximum table width
+	// row-splitter junctions (optional): CD CU
+	// head-splitter junctions (optional): CDH CUH
+	Columns  []Column  // Column definitions
+	MaxWidth int       // maximum table width, 0 for terminal width, <0 for unlimited
+	SortBy   []SortKey // sort rows before printing, see ParseSortKeys
+
+	// GroupBy prints rows grouped by the value of the column (which doesn't
+	// need to be in Columns), each group headed by a full-width row styled
+	// with class 'table:group:field'. GroupTotals adds a row of column
+	// aggregates after each group, with class 'table:subtotal:field'.
+	GroupBy     *Column
+	GroupTotals bool
+
+	// ColumnGroups adds a header line above the column titles, each group
+	// spanning its adjacent columns, styled with class 'table:colgroup:title'.
+	ColumnGroups []ColumnGroup
+
+	// Plain prints kubectl-style output: no borders or lines, upper case
+	// titles and columns separated by Gutter spaces (DefaultGutter if <= 0),
+	// with trailing whitespace trimmed. Border is ignored.
+	Plain  bool
+	Gutter int
+
+	// Layout is LayoutHorizontal (default), LayoutVertical to print a block
+	// of "Title: value" lines per row with a record line styled with class
+	// 'table:record:', or LayoutAuto to print vertically if the table is
+	// too wide for MaxWidth even with the columns shrunk to their MinWidth.
+	Layout int
+
+	// SampleRows is the number of rows used by Stream to decide auto column
+	// widths, DefaultSampleRows if <= 0.
+	SampleRows int
 
-	columns    []Column // actuall columns
 	hiddenCols map[string]bool
 }
 
@@ -108,22 +161,223 @@ func (tv *Table) HideColumns(names ...string) *Table {
 	return tv
 }
 
-func (tv *Table) Print(data []map[string]interface{}) {
-	// calculate column width
-	tv.columns = make([]Column, 0, len(tv.Columns))
-	fixedWidth := 0
-	for i := 0; i < len(tv.Columns); i++ {
-		if tv.hiddenCols != nil && tv.hiddenCols[strings.ToLower(tv.Columns[i].Title)] {
-			continue
+// Print prints data as a table. data is a slice (or array) of
+// map[string]interface{}, other maps with string keys, structs or pointers to
+// them. For structs, Column.Field is resolved against the exported field
+// names as well as the names in `cliview:"..."` and `json:"..."` tags,
+// fields of embedded structs are promoted. It returns an error if data is
+// not a slice of such rows or writing fails. The configuration is not
+// validated: a malformed Border falls back to the missing parts of
+// BorderDouble, see Validate.
+func (tv *Table) Print(data interface{}) error {
+	rows, err := tv.rows(data)
+	if err != nil {
+		return err
+	}
+	_, err = tv.render(tv.Out(), rows)
+	return err
+}
+
+// Render prints data like Print to w and returns the number of bytes
+// written. It returns an error if data can't be printed, the configuration
+// isn't valid or writing fails, which stops printing.
+func (tv *Table) Render(w io.Writer, data interface{}) (int64, error) {
+	if err := tv.Validate(); err != nil {
+		return 0, err
+	}
+	rows, err := tv.rows(data)
+	if err != nil {
+		return 0, err
+	}
+	return tv.render(w, rows)
+}
+
+// Sprint returns data printed like Print as a string, without writing to
+// Output.Writer. If MaxWidth is 0, the width is Output.DefaultWidth unless
+// Output.Terminal tells otherwise, i.e. not limited by default.
+func (tv *Table) Sprint(data interface{}) string {
+	buf := new(bytes.Buffer)
+	if rows, err := tv.rows(data); err == nil {
+		tv.render(buf, rows)
+	}
+	return buf.String()
+}
+
+// Validate checks the configuration of the table: the Border, the column
+// widths and the Layout.
+func (tv *Table) Validate() error {
+	if tv.Border != "" && !tv.Plain {
+		if _, err := ParseBorder(tv.Border); err != nil {
+			return err
+		}
+	}
+	percentage := 0
+	for _, col := range tv.visibleColumns() {
+		if col.Width < 0 {
+			percentage -= col.Width
+		}
+		if col.MaxWidth < 0 || col.MinWidth < 0 {
+			return fmt.Errorf("cliview: negative MaxWidth or MinWidth of column %q", col.Title)
+		}
+	}
+	if percentage > 100 {
+		return fmt.Errorf("cliview: column width percentages sum up to %d%%", percentage)
+	}
+	if tv.Layout < LayoutHorizontal || tv.Layout > LayoutAuto {
+		return fmt.Errorf("cliview: unknown layout %d", tv.Layout)
+	}
+	return nil
+}
+
+// rows converts data into table rows, which are sorted when printed.
+func (tv *Table) rows(data interface{}) ([]map[string]interface{}, error) {
+	return tableRows(data)
+}
+
+func (tv *Table) render(w io.Writer, data []map[string]interface{}) (int64, error) {
+	r := tv.newRender(w)
+	data, grid := tv.sortRows(data, r.columns, r.grid(data))
+	r.print(grid, data)
+	return r.w.n, r.w.err
+}
+
+func (r *tableRender) print(grid [][]tableCell, data []map[string]interface{}) {
+	tv := r.view
+	if tv.Layout == LayoutVertical {
+		r.printVertical(grid)
+		return
+	}
+
+	footVals, foot := tv.aggregate(r.columns, grid, "table:foot:")
+	groups := tv.group(r.columns, data, grid)
+	totals := make([][]string, 0, len(groups)+1)
+	if foot != nil {
+		totals = append(totals, foot)
+	}
+	for _, g := range groups {
+		if g.totals != nil {
+			totals = append(totals, g.totals)
+		}
+	}
+	if !r.layout(grid, totals) && tv.Layout == LayoutAuto {
+		r.printVertical(grid)
+		return
+	}
+	if !r.printable() {
+		return
+	}
+
+	r.printHead()
+	if tv.GroupBy == nil {
+		for _, cells := range grid {
+			r.printData(cells)
+		}
+	} else {
+		for _, g := range groups {
+			r.printGroup(g)
+			for _, cells := range g.rows {
+				r.printData(cells)
+			}
+			if g.totals != nil {
+				r.printTotals("subtotal", g.totalVals, g.totals, r.rowSepOff, 4)
+			}
+		}
+	}
+	if foot != nil {
+		r.printTotals("foot", footVals, foot, r.headSepOff, r.headRowOff)
+	}
+	r.printBottom()
+}
+
+// tableRender is the state of printing a table.
+type tableRender struct {
+	view     *Table
+	w        *errWriter
+	maxWidth int      // maximum table width, <=0 for unlimited
+	columns  []Column // visible columns with actual widths
+	sepWidth int      // width of the column separator
+	gutter   string   // column separator in Plain mode
+	chars    []rune   // border runes
+
+	// offsets of the lines in chars, -1 if left out
+	topOff, headRowOff, headSepOff, rowSepOff, bottomOff int
+
+	sepOff int    // separator line above the next row
+	above  []bool // column boundaries of the last row
+	colors int    // color level of the output
+}
+
+func (tv *Table) newRender(w io.Writer) *tableRender {
+	r := &tableRender{
+		view:     tv,
+		w:        &errWriter{w: w},
+		maxWidth: tv.MaxWidth,
+		columns:  tv.visibleColumns(),
+		chars:    tv.borderRunes(),
+		colors:   tv.colorLevel(w),
+	}
+	if r.maxWidth == 0 {
+		r.maxWidth = tv.terminalWidth(w)
+	}
+	if r.maxWidth <= 0 {
+		// percentages of an unlimited width are taken as auto widths
+		for i := range r.columns {
+			if r.columns[i].Width < 0 {
+				r.columns[i].Width = 0
+			}
 		}
-		col := tv.Columns[i]
+	}
+	r.sepWidth = runeWidth(r.chars[5])
+	if tv.Plain {
+		r.gutter = PaddingString(tv.Gutter)
+		if tv.Gutter <= 0 {
+			r.gutter = PaddingString(DefaultGutter)
+		}
+		r.sepWidth = len(r.gutter)
+	}
+
+	// a line is left out if it has no fill rune, or no left rune while the
+	// rows next to it have a left border
+	lineOff := func(off, rowOff int) int {
+		if r.chars[off+1] == 0 || r.chars[off] == 0 && r.chars[rowOff] != 0 {
+			return -1
+		}
+		return off
+	}
+	r.headRowOff = 15
+	r.topOff = lineOff(0, r.headRowOff)
+	r.rowSepOff = lineOff(11, 4)
+	r.headSepOff = lineOff(18, r.headRowOff)
+	r.bottomOff = lineOff(7, 4)
+	r.sepOff = r.topOff
+	return r
+}
+
+// layout decides the column widths from the sample rows and the formatted
+// totals, shrinking the columns to fit maxWidth. It returns false if the
+// columns don't fit even when shrunk to their minimum widths.
+func (r *tableRender) layout(sample [][]tableCell, totals [][]string) bool {
+	tv := r.view
+	fixedWidth := 0
+	for i := range r.columns {
+		col := &r.columns[i]
 		if col.Width > 0 {
-			fixedWidth += tv.Columns[i].Width
+			fixedWidth += col.Width
 		} else if col.Width == 0 {
-			width := textWidth(col.Title)
-			for _, v := range data {
-				valLen := textWidth(tv.formatCell("table:row:", col, v))
-				if valLen > width {
+			width := col.cellWidth(tv.title(*col))
+			for _, cells := range sample {
+				if cells[i].span != 1 {
+					continue
+				}
+				if valLen := col.cellWidth(cells[i].text); valLen > width {
+					width = valLen
+				}
+				if col.MaxWidth > 0 && width > col.MaxWidth {
+					width = col.MaxWidth
+				}
+			}
+			for _, strs := range totals {
+				if valLen := col.cellWidth(strs[i]); valLen > width {
 					width = valLen
 				}
 				if col.MaxWidth > 0 && width > col.MaxWidth {
@@ -133,91 +387,270 @@ func (tv *Table) Print(data []map[string]interface{}) {
 			col.Width = width
 			fixedWidth += width
 		}
-		tv.columns = append(tv.columns, col)
 	}
-	restWidth := tv.MaxWidth - fixedWidth - len(tv.columns) - 1
+	borderWidth := runeWidth(r.chars[4]) + runeWidth(r.chars[6])
+	if len(r.columns) > 1 {
+		borderWidth += (len(r.columns) - 1) * r.sepWidth
+	}
+
+	// shrink columns to fit, percentage columns reserve their MinWidth
+	fits := true
+	if r.maxWidth > 0 {
+		natural := fixedWidth + borderWidth
+		for _, col := range r.columns {
+			if col.Width < 0 && col.MinWidth > 0 {
+				natural += col.MinWidth
+			}
+		}
+		if excess := natural - r.maxWidth; excess > 0 {
+			taken := shrinkColumns(r.columns, excess)
+			fixedWidth -= taken
+			fits = taken == excess
+		}
+	}
+	restWidth := r.maxWidth - fixedWidth - borderWidth
+	if restWidth < 0 {
+		restWidth = 0
+	}
 
 	// calculate final width
-	width := 1
-	for i := 0; i < len(tv.columns); i++ {
-		if tv.columns[i].Width < 0 {
-			tv.columns[i].Width = restWidth * -tv.columns[i].Width / 100
+	for i := range r.columns {
+		if col := &r.columns[i]; col.Width < 0 {
+			col.Width = restWidth * -col.Width / 100
+			if col.Width < col.MinWidth {
+				col.Width = col.MinWidth
+			}
 		}
-		if tv.columns[i].Width > 0 {
-			width += tv.columns[i].Width
+	}
+	return fits
+}
+
+func (r *tableRender) style(class, text string, data interface{}, styler StylerFunc) string {
+	return r.view.style(r.colors, class, text, data, styler)
+}
+
+// printable tells if the columns are wide enough to be printed.
+func (r *tableRender) printable() bool {
+	width := 1
+	for _, col := range r.columns {
+		if col.Width > 0 {
+			width += col.Width
 		}
 		width++
 	}
+	return width > 2
+}
 
-	if width <= 2 {
-		return
+func (r *tableRender) printHead() {
+	tv := r.view
+	if spans := tv.columnGroupSpans(r.columns); spans != nil {
+		row := r.startRow(r.sepOff, r.headRowOff)
+		for _, span := range spans {
+			cell := printCell{class: "table:colgroup:", span: span.count}
+			text := ""
+			if g := span.group; g != nil {
+				cell.class += g.Title
+				cell.data = g.Title
+				cell.align = g.Align
+				cell.styler = g.Styler
+				text = g.Title
+				if tv.Plain {
+					text = strings.ToUpper(text)
+				}
+			}
+			row.add(cell, text)
+		}
+		row.end()
+		r.sepOff = r.rowSepOff
 	}
 
-	chars := charsInString(tv.Border)
-	if len(chars) < 11 {
-		chars = runesBorderFull
+	row := r.startRow(r.sepOff, r.headRowOff)
+	for i, c := range r.columns {
+		row.column("head", tv.title(c), i, c.Title)
 	}
+	row.end()
+	r.sepOff = r.headSepOff
+}
 
-	// print head
-	rowSepOff := 11
-	headRowOff := 15
-	headSepOff := 18
-	if len(chars) < 15 {
-		rowSepOff = -1
-		headSepOff = -1
-		headRowOff = 4
-	} else if len(chars) < 18 {
-		headRowOff = 4
-		headSepOff = rowSepOff
-	} else if len(chars) < 22 {
-		headSepOff = rowSepOff
+// tableCell is a fetched and formatted cell.
+type tableCell struct {
+	value interface{} // fetched value, unwrapped from a Span
+	data  interface{} // data passed to the Styler
+	text  string      // formatted value
+	span  int         // number of columns covered, 0 if covered by another cell
+}
+
+// grid fetches and formats the cells of all rows, so that Fetchers and
+// Formatters are called only once per cell.
+func (r *tableRender) grid(data []map[string]interface{}) [][]tableCell {
+	grid := make([][]tableCell, len(data))
+	for n, d := range data {
+		grid[n] = r.cells(d)
 	}
+	return grid
+}
 
-	if rowSepOff >= 0 && chars[rowSepOff] == 0 {
-		rowSepOff = -1
+// cells fetches and formats the cells of a row. The cells covered by a Span
+// are not fetched.
+func (r *tableRender) cells(d map[string]interface{}) []tableCell {
+	tv := r.view
+	cells := make([]tableCell, len(r.columns))
+	for i := 0; i < len(r.columns); i++ {
+		c := r.columns[i]
+		fetched := tv.fetchCell(c, d)
+		if sp, spanned := spanOf(fetched); spanned {
+			count := sp.Columns
+			if count <= 0 || i+count > len(r.columns) {
+				count = len(r.columns) - i
+			}
+			cells[i] = tableCell{
+				value: sp.Value,
+				data:  sp.Value,
+				text:  tv.formatValue("table:row:"+c.Field, c, sp.Value),
+				span:  count,
+			}
+			i += count - 1
+			continue
+		}
+		cells[i] = tableCell{
+			value: fetched,
+			data:  d[c.Field],
+			text:  tv.formatValue("table:row:"+c.Field, c, fetched),
+			span:  1,
+		}
 	}
-	if headSepOff >= 0 && chars[headSepOff] == 0 {
-		headSepOff = -1
+	return cells
+}
+
+func (r *tableRender) printData(cells []tableCell) {
+	row := r.startRow(r.sepOff, 4)
+	r.sepOff = r.rowSepOff
+	for i, cell := range cells {
+		c := r.columns[i]
+		switch cell.span {
+		case 0:
+		case 1:
+			row.column("row", cell.text, i, cell.data)
+		default:
+			row.add(printCell{
+				class:  "table:row:" + c.Field,
+				data:   cell.data,
+				span:   cell.span,
+				align:  c.Align,
+				wrap:   c.Wrap,
+				styler: c.Styler,
+			}, cell.text)
+		}
 	}
+	row.end()
+}
 
-	row := tv.startPrintRow(chars, 0, headRowOff)
-	for i, c := range tv.columns {
-		row.column("head", c.Title, i, c.Title)
+func (r *tableRender) printGroup(g *tableGroup) {
+	tv := r.view
+	row := r.startRow(r.sepOff, 4)
+	r.sepOff = r.rowSepOff
+	row.add(printCell{
+		class:  "table:group:" + tv.GroupBy.Field,
+		data:   g.value,
+		span:   len(r.columns),
+		align:  tv.GroupBy.Align,
+		styler: tv.GroupBy.Styler,
+	}, g.label)
+	row.end()
+}
+
+func (r *tableRender) printTotals(class string, vals []interface{}, strs []string, offSep, offRow int) {
+	row := r.startRow(offSep, offRow)
+	for i, c := range r.columns {
+		if c.Width > 0 {
+			row.column(class, strs[i], i, vals[i])
+		} else {
+			row.column(class, "", i, vals[i])
+		}
 	}
 	row.end()
+}
 
-	// print rows
-	sepOff := headSepOff
-	for _, d := range data {
-		row = tv.startPrintRow(chars, sepOff, 4)
-		sepOff = rowSepOff
-		for i, c := range tv.columns {
-			val := d[c.Field]
-			if c.Width > 0 {
-				valStr := tv.formatCell("table:row:", tv.columns[i], d)
-				row.column("row", valStr, i, val)
-			} else {
-				row.column("row", "", i, val)
-			}
+func (r *tableRender) printBottom() {
+	r.startRow(r.bottomOff, -1).end()
+}
+
+// borderRunes returns the complete runes of Border, or of BorderDouble if
+// Border has less than 11 runes.
+func (tv *Table) borderRunes() []rune {
+	chars := charsInString(tv.Border)
+	if tv.Plain {
+		chars = charsInString(BorderPlain)
+	} else if len(chars) < 11 {
+		chars = charsInString(BorderDouble)
+	}
+	return borderStyle(chars).Runes()
+}
+
+// DefaultGutter is the number of spaces between columns in Plain mode.
+const DefaultGutter = 3
+
+// title returns the title displayed for col.
+func (tv *Table) title(col Column) string {
+	if tv.Plain {
+		return strings.ToUpper(col.Title)
+	}
+	return col.Title
+}
+
+// visibleColumns returns the columns not hidden by HideColumns.
+func (tv *Table) visibleColumns() []Column {
+	columns := make([]Column, 0, len(tv.Columns))
+	for _, col := range tv.Columns {
+		if tv.hiddenCols != nil && tv.hiddenCols[strings.ToLower(col.Title)] {
+			continue
 		}
-		row.end()
+		columns = append(columns, col)
 	}
+	return columns
+}
 
-	row = tv.startPrintRow(chars, 7, -1)
-	for i, c := range tv.columns {
-		row.column("", c.Title, i, c.Title)
+// Span is a cell value which spans Columns columns from its own column, or
+// the rest of the row if Columns <= 0. It can be put in the row data or
+// returned by a Fetcher, the cells it covers are not printed.
+type Span struct {
+	Value   interface{}
+	Columns int
+}
+
+func spanOf(val interface{}) (Span, bool) {
+	switch sp := val.(type) {
+	case Span:
+		return sp, true
+	case *Span:
+		if sp != nil {
+			return *sp, true
+		}
 	}
-	row.end()
+	return Span{}, false
 }
 
-func (tv *Table) formatCell(classPrefix string, col Column, row map[string]interface{}) string {
-	var val interface{}
+// cellValue fetches the value of a cell, unwrapping a Span.
+func (tv *Table) cellValue(col Column, row map[string]interface{}) interface{} {
+	val := tv.fetchCell(col, row)
+	if sp, spanned := spanOf(val); spanned {
+		return sp.Value
+	}
+	return val
+}
+
+func (tv *Table) fetchCell(col Column, row map[string]interface{}) interface{} {
 	if col.Fetcher != nil {
-		val = col.Fetcher(col, row)
-	} else {
-		val = row[col.Field]
+		return col.Fetcher(col, row)
 	}
-	class := classPrefix + col.Field
+	return row[col.Field]
+}
+
+func (tv *Table) formatCell(classPrefix string, col Column, row map[string]interface{}) string {
+	return tv.formatValue(classPrefix+col.Field, col, tv.cellValue(col, row))
+}
+
+func (tv *Table) formatValue(class string, col Column, val interface{}) string {
 	if col.Formatter != nil {
 		return col.Formatter(class, val, func(class string, data interface{}, formatter FormatterFunc) string {
 			return tv.Format(class, data)
@@ -226,20 +659,244 @@ func (tv *Table) formatCell(classPrefix string, col Column, row map[string]inter
 	return tv.Format(class, val)
 }
 
-func ellipsis(text string, width int) string {
-	if textWidth(text) <= width {
-		return text
+type columnGroupSpan struct {
+	group *ColumnGroup
+	count int
+}
+
+// columnGroupSpans lays out ColumnGroups over the visible columns. Each
+// group spans its adjacent visible columns, the others get blank cells. It
+// returns nil if no group has visible columns.
+func (tv *Table) columnGroupSpans(columns []Column) []columnGroupSpan {
+	var spans []columnGroupSpan
+	grouped := false
+	for _, col := range columns {
+		var group *ColumnGroup
+		for i := range tv.ColumnGroups {
+			for _, field := range tv.ColumnGroups[i].Fields {
+				if field == col.Field {
+					group = &tv.ColumnGroups[i]
+				}
+			}
+		}
+		if n := len(spans); group != nil && n > 0 && spans[n-1].group == group {
+			spans[n-1].count++
+			continue
+		}
+		spans = append(spans, columnGroupSpan{group: group, count: 1})
+		grouped = grouped || group != nil
+	}
+	if !grouped {
+		return nil
 	}
-	chars := charsInString(text)
-	if width <= 3 {
-		text = string(chars[0:1])
-		for i := 1; i < width; i++ {
-			text += "."
+	return spans
+}
+
+type tableGroup struct {
+	value     interface{}
+	label     string
+	rows      [][]tableCell
+	totalVals []interface{}
+	totals    []string
+}
+
+// group splits the grid of data by the GroupBy value in the order the
+// groups first appear. Groups are keyed by the formatted value, which is also
+// used as the group label, prefixed with the GroupBy title if set.
+func (tv *Table) group(columns []Column, data []map[string]interface{}, grid [][]tableCell) []*tableGroup {
+	if tv.GroupBy == nil {
+		return nil
+	}
+	groups := make([]*tableGroup, 0)
+	index := make(map[string]*tableGroup)
+	for n, row := range data {
+		val := tv.fetchCell(*tv.GroupBy, row)
+		str := tv.formatValue("table:group:"+tv.GroupBy.Field, *tv.GroupBy, val)
+		g := index[str]
+		if g == nil {
+			g = &tableGroup{value: val, label: str}
+			if tv.GroupBy.Title != "" {
+				g.label = tv.GroupBy.Title + ": " + str
+			}
+			index[str] = g
+			groups = append(groups, g)
 		}
-	} else {
-		text = string(chars[0:width-3]) + "..."
+		g.rows = append(g.rows, grid[n])
 	}
-	return text
+	if tv.GroupTotals {
+		for _, g := range groups {
+			g.totalVals, g.totals = tv.aggregate(columns, g.rows, "table:subtotal:")
+		}
+	}
+	return groups
+}
+
+// aggregate computes the footer values and their formatted strings, both
+// are nil if no column has an Aggregate.
+func (tv *Table) aggregate(columns []Column, grid [][]tableCell, classPrefix string) ([]interface{}, []string) {
+	if !hasAggregate(columns) {
+		return nil, nil
+	}
+	values := make([][]interface{}, len(columns))
+	for i, col := range columns {
+		if col.Aggregate == nil {
+			continue
+		}
+		values[i] = make([]interface{}, len(grid))
+		for n, cells := range grid {
+			values[i][n] = cells[i].value
+		}
+	}
+	return tv.aggregateValues(columns, values, classPrefix)
+}
+
+func hasAggregate(columns []Column) bool {
+	for _, col := range columns {
+		if col.Aggregate != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregateValues aggregates the values of each column.
+func (tv *Table) aggregateValues(columns []Column, values [][]interface{}, classPrefix string) ([]interface{}, []string) {
+	vals := make([]interface{}, len(columns))
+	strs := make([]string, len(columns))
+	for i, col := range columns {
+		if col.Aggregate == nil {
+			continue
+		}
+		vals[i] = col.Aggregate(values[i])
+		strs[i] = tv.formatValue(classPrefix+col.Field, col, vals[i])
+	}
+	return vals, strs
+}
+
+const defaultMinWidth = 3
+
+func (col *Column) minWidth() int {
+	min := col.MinWidth
+	if min <= 0 {
+		min = defaultMinWidth
+	}
+	if min > col.Width {
+		min = col.Width
+	}
+	return min
+}
+
+// shrinkColumns narrows fixed and auto-width columns by up to excess cells
+// and returns the width actually taken. Columns are shrunk from the lowest
+// Priority up, columns with the same priority in proportion to how much they
+// can give before reaching their minimum width.
+func shrinkColumns(columns []Column, excess int) int {
+	priorities := make([]int, 0, len(columns))
+	for _, col := range columns {
+		priorities = append(priorities, col.Priority)
+	}
+	sort.Ints(priorities)
+
+	taken := 0
+	for n, priority := range priorities {
+		if excess <= 0 {
+			break
+		}
+		if n > 0 && priorities[n-1] == priority {
+			continue
+		}
+		slacks := make(map[int]int)
+		total := 0
+		for i := range columns {
+			if col := &columns[i]; col.Width > 0 && col.Priority == priority {
+				if slack := col.Width - col.minWidth(); slack > 0 {
+					slacks[i] = slack
+					total += slack
+				}
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		if excess >= total {
+			for i, slack := range slacks {
+				columns[i].Width -= slack
+			}
+			excess -= total
+			taken += total
+			continue
+		}
+		cut := 0
+		for i, slack := range slacks {
+			c := excess * slack / total
+			columns[i].Width -= c
+			slacks[i] -= c
+			cut += c
+		}
+		// hand out the rounding remainder from the left
+		for i := 0; cut < excess && i < len(columns); i++ {
+			if slacks[i] > 0 {
+				columns[i].Width--
+				cut++
+			}
+		}
+		taken += excess
+		excess = 0
+	}
+	return taken
+}
+
+// cellWidth returns the width needed to display text without truncation.
+func (col *Column) cellWidth(text string) int {
+	if !col.Wrap {
+		return textWidth(text)
+	}
+	width := 0
+	for _, line := range strings.Split(text, "\n") {
+		if w := textWidth(line); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// cellLines lays out text in width as one or more lines.
+func cellLines(text string, width int, wrap bool) []string {
+	if wrap {
+		return wrapText(text, width)
+	}
+	return []string{ellipsis(text, width)}
+}
+
+// wrapText word-wraps text into lines not wider than width, embedded
+// newlines always start a new line and words longer than width are broken.
+// Styling from ANSI escape sequences is restored on each line.
+func wrapText(text string, width int) []string {
+	lines := make([]string, 0, 1)
+	for _, para := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			if line != "" && textWidth(line)+1+textWidth(word) <= width {
+				line += " " + word
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for textWidth(word) > width {
+				head, tail := splitWidth(word, width)
+				if tail == "" {
+					word = head
+					break
+				}
+				lines = append(lines, head)
+				word = tail
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return balanceStyles(lines)
 }
 
 func wrapLen(text string, width int, align int) string {
@@ -262,60 +919,181 @@ func wrapLen(text string, width int, align int) string {
 	return text
 }
 
+type printCell struct {
+	class  string
+	lines  []string
+	data   interface{}
+	col    int // first column
+	span   int // number of columns covered
+	align  int
+	wrap   bool
+	styler StylerFunc
+}
+
 type printRow struct {
-	bufSep, bufRow *bytes.Buffer
-	view           *Table
-	border         []rune
+	r              *tableRender
 	offSep, offRow int
-	writer         io.Writer
+	cells          []printCell
 }
 
-func (tv *Table) startPrintRow(chars []rune, offSep, offRow int) *printRow {
-	row := &printRow{
-		bufSep: tv.PaddingBuffer(),
-		bufRow: tv.PaddingBuffer(),
-		view:   tv,
-		border: chars,
-		offSep: offSep,
-		offRow: offRow,
-		writer: tv.Out(),
-	}
-	return row
+// startRow starts a row with the separator line at offSep and the row line
+// at offRow in the border runes, either can be -1 to skip the line.
+func (r *tableRender) startRow(offSep, offRow int) *printRow {
+	return &printRow{r: r, offSep: offSep, offRow: offRow}
 }
 
 func (row *printRow) column(class, text string, col int, data interface{}) {
-	addSep := 2
-	addRow := 1
-	if col == 0 {
-		addSep = 0
-		addRow = 0
+	c := &row.r.columns[col]
+	row.add(printCell{
+		class:  "table:" + class + ":" + c.Field,
+		data:   data,
+		col:    col,
+		span:   1,
+		align:  c.Align,
+		wrap:   c.Wrap,
+		styler: c.Styler,
+	}, text)
+}
+
+// add appends a cell after the cells already added to the row.
+func (row *printRow) add(cell printCell, text string) {
+	n := len(row.r.columns)
+	if len(row.cells) > 0 {
+		last := row.cells[len(row.cells)-1]
+		cell.col = last.col + last.span
+	}
+	if cell.span <= 0 || cell.col+cell.span > n {
+		cell.span = n - cell.col
+	}
+	if width := row.r.spanWidth(cell.col, cell.span); width > 0 {
+		cell.lines = cellLines(text, width, cell.wrap)
 	}
-	c := &row.view.columns[col]
+	row.cells = append(row.cells, cell)
+}
 
-	if row.offSep >= 0 {
-		row.bufSep.WriteRune(row.border[row.offSep+addSep])
-		if c.Width > 0 {
-			for i := 0; i < c.Width; i++ {
-				row.bufSep.WriteRune(row.border[row.offSep+1])
-			}
+// spanWidth returns the width of count columns from col, including the
+// separators between them.
+func (r *tableRender) spanWidth(col, count int) int {
+	width := (count - 1) * r.sepWidth
+	for i := col; i < col+count; i++ {
+		if w := r.columns[i].Width; w > 0 {
+			width += w
 		}
 	}
-	if row.offRow >= 0 {
-		row.bufRow.WriteRune(row.border[row.offRow+addRow])
-		if c.Width > 0 {
-			row.bufRow.WriteString(row.view.Styling("table:"+class+":"+c.Field, wrapLen(ellipsis(text, c.Width), c.Width, c.Align), data, c.Styler))
+	return width
+}
+
+// junctions returns the runes of the separator line at off where the
+// column separators above and below meet, only go down or only go up.
+func junctions(border []rune, off int) (both, down, up rune) {
+	both = border[off+2]
+	down, up = both, both
+	switch off {
+	case 0:
+		up = border[off+1]
+	case 7:
+		down = border[off+1]
+	default:
+		ext := 22 // row-splitter: CD CU
+		if off == 18 {
+			ext = 24 // head-splitter: CDH CUH
+		}
+		if border[ext] != 0 && border[ext+1] != 0 {
+			down, up = border[ext], border[ext+1]
 		}
 	}
+	return
 }
 
+// end prints the row and keeps after which columns it has a separator, for
+// choosing the junctions in the separator line of the next row.
 func (row *printRow) end() {
-	if row.offSep >= 0 {
-		row.bufSep.WriteRune(row.border[row.offSep+3])
-		fmt.Fprintln(row.writer, row.bufSep.String())
+	r := row.r
+	if r.w.err != nil {
+		return
 	}
+	n := len(r.columns)
+	below := make([]bool, n)
 	if row.offRow >= 0 {
-		row.bufRow.WriteRune(row.border[row.offRow+2])
-		fmt.Fprintln(row.writer, row.bufRow.String())
+		for _, cell := range row.cells {
+			below[cell.col+cell.span-1] = true
+		}
+	}
+
+	if row.offSep >= 0 {
+		bufSep := r.view.PaddingBuffer()
+		both, down, up := junctions(r.chars, row.offSep)
+		writeBorder(bufSep, r.chars[row.offSep])
+		for i, c := range r.columns {
+			if i > 0 && r.sepWidth > 0 {
+				above := r.above != nil && r.above[i-1]
+				switch {
+				case above && below[i-1]:
+					writeBorder(bufSep, both)
+				case below[i-1]:
+					writeBorder(bufSep, down)
+				case above:
+					writeBorder(bufSep, up)
+				default:
+					writeBorder(bufSep, r.chars[row.offSep+1])
+				}
+			}
+			for k := 0; k < c.Width; k++ {
+				writeBorder(bufSep, r.chars[row.offSep+1])
+			}
+		}
+		writeBorder(bufSep, r.chars[row.offSep+3])
+		fmt.Fprintln(r.w, bufSep.String())
+	}
+	r.above = below
+	if row.offRow < 0 {
+		return
+	}
+
+	height := 1
+	for _, cell := range row.cells {
+		if len(cell.lines) > height {
+			height = len(cell.lines)
+		}
+	}
+	for n := 0; n < height; n++ {
+		bufRow := r.view.PaddingBuffer()
+		for i, cell := range row.cells {
+			if i == 0 {
+				writeBorder(bufRow, r.chars[row.offRow])
+			} else if r.gutter != "" {
+				bufRow.WriteString(r.gutter)
+			} else {
+				writeBorder(bufRow, r.chars[row.offRow+1])
+			}
+			width := r.spanWidth(cell.col, cell.span)
+			if width <= 0 {
+				continue
+			}
+			line := ""
+			if n < len(cell.lines) {
+				line = cell.lines[n]
+			}
+			text := wrapLen(line, width, cell.align)
+			if r.view.Plain && i == len(row.cells)-1 {
+				// trimmed before styling, which may end the text in an escape sequence
+				text = strings.TrimRight(text, " ")
+			}
+			bufRow.WriteString(r.style(cell.class, text, cell.data, cell.styler))
+		}
+		writeBorder(bufRow, r.chars[row.offRow+2])
+		line := bufRow.String()
+		if r.view.Plain {
+			line = strings.TrimRight(line, " ")
+		}
+		fmt.Fprintln(r.w, line)
+	}
+}
+
+// writeBorder writes a border rune unless it is 0, which leaves it out.
+func writeBorder(buf *bytes.Buffer, r rune) {
+	if r != 0 {
+		buf.WriteRune(r)
 	}
 }
 
@@ -331,7 +1109,3 @@ func charsInString(text string) []rune {
 	}
 	return chars
 }
-
-func textWidth(text string) int {
-	return utf8.RuneCountInString(text)
-}
@@ -2,6 +2,7 @@ package cliview
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 )
@@ -177,17 +178,17 @@ func TestTablePrintStyling(t *testing.T) {
 	})
 	result := buf.String()
 	if result != ""+
-		"+------+--+\n"+
-		"|    C1|C2|\n"+
-		"+------+--+\n"+
-		"|123456|<table:row:col2>  </table:row:col2>|\n"+
-		"+------+--+\n"+
-		"|    12|<table:row:col2>  </table:row:col2>|\n"+
-		"+------+--+\n"+
-		"|   123|<table:row:col2>  </table:row:col2>|\n"+
-		"+------+--+\n"+
-		"|<table:row:col1>      </table:row:col1>|<table:row:col2>  </table:row:col2>|\n"+
-		"+------+--+\n" {
+		"+----+--+\n"+
+		"|  C1|C2|\n"+
+		"+----+--+\n"+
+		"|1...|<table:row:col2>  </table:row:col2>|\n"+
+		"+----+--+\n"+
+		"|  12|<table:row:col2>  </table:row:col2>|\n"+
+		"+----+--+\n"+
+		"| 123|<table:row:col2>  </table:row:col2>|\n"+
+		"+----+--+\n"+
+		"|<table:row:col1>    </table:row:col1>|<table:row:col2>  </table:row:col2>|\n"+
+		"+----+--+\n" {
 		t.Errorf("Unexpected output\n%v", result)
 	}
 }
@@ -227,6 +228,30 @@ func TestTableColumnFormatter(t *testing.T) {
 	}
 }
 
+func TestTablePrintFloats(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "F32", Field: "f32"},
+			Column{Title: "F64", Field: "f64"},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"f32": float32(0.25), "f64": 1e21},
+	})
+	result := buf.String()
+	if result != ""+
+		"+----+-----+\n"+
+		"|F32 |F64  |\n"+
+		"+----+-----+\n"+
+		"|0.25|1e+21|\n"+
+		"+----+-----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
 func TestTableColumnFetcher(t *testing.T) {
 	buf := new(bytes.Buffer)
 	tv := &Table{
@@ -258,3 +283,769 @@ func TestTableColumnFetcher(t *testing.T) {
 		t.Errorf("Unexpected output\n%v", result)
 	}
 }
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testRecord struct {
+	testBase
+	Name   string
+	Age    int    `cliview:"age"`
+	Secret string `cliview:"-"`
+	hidden string
+}
+
+func TestTablePrintStructs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id"},
+			Column{Title: "Name", Field: "Name"},
+			Column{Title: "Age", Field: "age", Align: AlignRight},
+			Column{Title: "Secret", Field: "Secret"},
+			Column{Title: "Next", Field: "next",
+				Fetcher: func(col Column, row map[string]interface{}) interface{} {
+					return row["Age"].(int) + 1
+				},
+			},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]*testRecord{
+		&testRecord{testBase: testBase{ID: "a1"}, Name: "Jack", Age: 30, Secret: "x", hidden: "y"},
+		&testRecord{testBase: testBase{ID: "b2"}, Name: "Alice", Age: 7},
+	})
+	result := buf.String()
+	if result != ""+
+		"+--+-----+---+------+----+\n"+
+		"|ID|Name |Age|Secret|Next|\n"+
+		"+--+-----+---+------+----+\n"+
+		"|a1|Jack | 30|      |31  |\n"+
+		"+--+-----+---+------+----+\n"+
+		"|b2|Alice|  7|      |8   |\n"+
+		"+--+-----+---+------+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+type testEmbedNode struct {
+	*testEmbedNode
+	Name string
+}
+
+func TestTablePrintEmbeddingCycle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output:  Output{Writer: buf},
+		Columns: []Column{Column{Title: "Name", Field: "Name"}},
+		Border:  TestBorder,
+	}
+	if err := tv.Print([]testEmbedNode{{Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	result := buf.String()
+	if result != ""+
+		"+----+\n"+
+		"|Name|\n"+
+		"+----+\n"+
+		"|a   |\n"+
+		"+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintTypedMaps(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "K", Field: "k"},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]string{{"k": "v"}})
+	result := buf.String()
+	if result != ""+
+		"+-+\n"+
+		"|K|\n"+
+		"+-+\n"+
+		"|v|\n"+
+		"+-+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	buf.Reset()
+	if err := tv.Print([]int{1}); err == nil || buf.Len() != 0 {
+		t.Errorf("Print should fail for unsupported rows: %v\n%v", err, buf.String())
+	}
+	if err := tv.Print(map[string]string{"k": "v"}); err == nil {
+		t.Errorf("Print should fail for data which isn't a slice")
+	}
+}
+
+func TestTablePrintWrap(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id", Align: AlignRight},
+			Column{Title: "Description", Field: "desc", Width: 10, Wrap: true},
+			Column{Title: "Address", Field: "addr", Wrap: true},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{
+			"id":   1,
+			"desc": "a long description, wrapped",
+			"addr": "1 Main St\nSpringfield",
+		},
+		map[string]interface{}{
+			"id":   2,
+			"desc": "abcdefghijklmn",
+			"addr": "",
+		},
+	})
+	result := buf.String()
+	if result != ""+
+		"+--+----------+-----------+\n"+
+		"|ID|Descriptio|Address    |\n"+
+		"|  |n         |           |\n"+
+		"+--+----------+-----------+\n"+
+		"| 1|a long    |1 Main St  |\n"+
+		"|  |descriptio|Springfield|\n"+
+		"|  |n, wrapped|           |\n"+
+		"+--+----------+-----------+\n"+
+		"| 2|abcdefghij|           |\n"+
+		"|  |klmn      |           |\n"+
+		"+--+----------+-----------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintWideChars(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "名前", Field: "name"},
+			Column{Title: "Short", Field: "short", Width: 5},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"name": "東京", "short": "大阪市北区"},
+		map[string]interface{}{"name": "Tokyo", "short": "大阪"},
+	})
+	result := buf.String()
+	if result != ""+
+		"+-----+-----+\n"+
+		"|名前 |Short|\n"+
+		"+-----+-----+\n"+
+		"|東京 |大...|\n"+
+		"+-----+-----+\n"+
+		"|Tokyo|大阪 |\n"+
+		"+-----+-----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintTerminalWidth(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer: buf,
+			Terminal: func(w io.Writer) (int, bool) {
+				return 20, true
+			},
+		},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id"},
+			Column{Title: "Description", Field: "desc", Width: -100},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"id": 1, "desc": "a long description"},
+	})
+	result := buf.String()
+	if result != ""+
+		"+--+---------------+\n"+
+		"|ID|Description    |\n"+
+		"+--+---------------+\n"+
+		"|1 |a long descr...|\n"+
+		"+--+---------------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintPipedWidthPercentage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id"},
+			Column{Title: "Description", Field: "desc", Width: -100},
+		},
+		Border: TestBorder,
+	}
+	data := []map[string]interface{}{
+		map[string]interface{}{"id": 1, "desc": "a long description"},
+	}
+	tv.Print(data)
+	result := buf.String()
+	if result != ""+
+		"+--+------------------+\n"+
+		"|ID|Description       |\n"+
+		"+--+------------------+\n"+
+		"|1 |a long description|\n"+
+		"+--+------------------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	buf.Reset()
+	tv.DefaultWidth = 20
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"+--+---------------+\n"+
+		"|ID|Description    |\n"+
+		"+--+---------------+\n"+
+		"|1 |a long descr...|\n"+
+		"+--+---------------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintShrinkToFit(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id", Priority: 1},
+			Column{Title: "Name", Field: "name", Width: 12},
+			Column{Title: "Description", Field: "desc", MinWidth: 6},
+		},
+		MaxWidth: 30,
+		Border:   TestBorder,
+	}
+	data := []map[string]interface{}{
+		map[string]interface{}{"id": "i-0123456789", "name": "web server", "desc": "serves the public web site"},
+	}
+	tv.Print(data)
+	result := buf.String()
+	if result != ""+
+		"+------------+----+----------+\n"+
+		"|ID          |Name|Descrip...|\n"+
+		"+------------+----+----------+\n"+
+		"|i-0123456789|w...|serves ...|\n"+
+		"+------------+----+----------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	buf.Reset()
+	tv.MaxWidth = 20
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"+-------+---+------+\n"+
+		"|ID     |N..|Des...|\n"+
+		"+-------+---+------+\n"+
+		"|i-01...|w..|ser...|\n"+
+		"+-------+---+------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	// without MaxWidth, only a terminal makes the columns shrink
+	buf.Reset()
+	tv.MaxWidth = 0
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"+------------+------------+--------------------------+\n"+
+		"|ID          |Name        |Description               |\n"+
+		"+------------+------------+--------------------------+\n"+
+		"|i-0123456789|web server  |serves the public web site|\n"+
+		"+------------+------------+--------------------------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	buf.Reset()
+	tv.Terminal = func(w io.Writer) (int, bool) { return 30, true }
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"+------------+----+----------+\n"+
+		"|ID          |Name|Descrip...|\n"+
+		"+------------+----+----------+\n"+
+		"|i-0123456789|w...|serves ...|\n"+
+		"+------------+----+----------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTableGroupBy(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer: buf,
+			Styler: func(class, text string, data interface{}) string {
+				if strings.HasPrefix(class, "table:group:") {
+					return "<" + text + ">"
+				}
+				return text
+			},
+		},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "CPU", Field: "cpu", Align: AlignRight, Aggregate: AggregateSum},
+		},
+		GroupBy:     &Column{Title: "Region", Field: "region"},
+		GroupTotals: true,
+		Border:      TestBorder + "v^",
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"name": "web-server-1", "cpu": 2, "region": "us-east"},
+		map[string]interface{}{"name": "db1", "cpu": 16, "region": "eu-west"},
+		map[string]interface{}{"name": "web2", "cpu": 4, "region": "us-east"},
+	})
+	result := buf.String()
+	if result != ""+
+		"+------------+---+\n"+
+		"|Name        |CPU|\n"+
+		"+------------+---+\n"+
+		"|<Region: us-east >|\n"+
+		"+------------v---+\n"+
+		"|web-server-1|  2|\n"+
+		"+------------+---+\n"+
+		"|web2        |  4|\n"+
+		"+------------+---+\n"+
+		"|            |  6|\n"+
+		"+------------^---+\n"+
+		"|<Region: eu-west >|\n"+
+		"+------------v---+\n"+
+		"|db1         | 16|\n"+
+		"+------------+---+\n"+
+		"|            | 16|\n"+
+		"+------------+---+\n"+
+		"|            | 22|\n"+
+		"+------------+---+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTableColumnGroups(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Path", Field: "path"},
+			Column{Title: "Count", Field: "count"},
+			Column{Title: "p50", Field: "p50"},
+			Column{Title: "p99", Field: "p99"},
+			Column{Title: "Err", Field: "err"},
+		},
+		ColumnGroups: []ColumnGroup{
+			ColumnGroup{Title: "Requests", Fields: []string{"count", "p50", "p99"}, Align: AlignMiddle},
+		},
+		Border: TestBorder + "v^",
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"path": "/api", "count": 1200, "p50": "12ms", "p99": "140ms", "err": 2},
+	})
+	result := buf.String()
+	if result != ""+
+		"+----+----------------+---+\n"+
+		"|    |    Requests    |   |\n"+
+		"+----+-----v----v-----+---+\n"+
+		"|Path|Count|p50 |p99  |Err|\n"+
+		"+----+-----+----+-----+---+\n"+
+		"|/api|1200 |12ms|140ms|2  |\n"+
+		"+----+-----+----+-----+---+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTableSpanCells(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id"},
+			Column{Title: "Status", Field: "status"},
+			Column{Title: "Size", Field: "size", Align: AlignRight},
+			Column{Title: "Age", Field: "age"},
+		},
+		Border: TestBorder + "v^",
+	}
+	tv.Print([]map[string]interface{}{
+		map[string]interface{}{"id": "a", "status": "ok", "size": 10, "age": "1d"},
+		map[string]interface{}{"id": "b", "status": Span{Value: "failed: timeout"}},
+		map[string]interface{}{"id": "c", "status": &Span{Value: "n/a", Columns: 2}, "age": "3d"},
+	})
+	result := buf.String()
+	if result != ""+
+		"+--+------+----+---+\n"+
+		"|ID|Status|Size|Age|\n"+
+		"+--+------+----+---+\n"+
+		"|a |ok    |  10|1d |\n"+
+		"+--+------^----^---+\n"+
+		"|b |failed: timeout|\n"+
+		"+--+-----------v---+\n"+
+		"|c |n/a        |3d |\n"+
+		"+--+-----------+---+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintPlain(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Ready", Field: "ready", Align: AlignRight},
+			Column{Title: "Status", Field: "status"},
+		},
+		Border: TestBorder,
+		Plain:  true,
+	}
+	data := []map[string]interface{}{
+		{"name": "web-1", "ready": "1/1", "status": "Running"},
+		{"name": "db-0", "ready": "0/1", "status": "Pending"},
+		{"name": "cache", "ready": "1/1", "status": "OK"},
+	}
+	tv.Print(data)
+	result := buf.String()
+	if result != ""+
+		"NAME    READY   STATUS\n"+
+		"web-1     1/1   Running\n"+
+		"db-0      0/1   Pending\n"+
+		"cache     1/1   OK\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	buf.Reset()
+	tv.Gutter = 1
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"NAME  READY STATUS\n"+
+		"web-1   1/1 Running\n"+
+		"db-0    0/1 Pending\n"+
+		"cache   1/1 OK\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	// the padding of the last cell is trimmed inside the styled text
+	buf.Reset()
+	tv.ColorMode = ColorModeAlways
+	tv.Styler = func(class, text string, data interface{}) string {
+		if strings.HasPrefix(class, "table:row:") {
+			return "\x1b[1m" + text + "\x1b[0m"
+		}
+		return text
+	}
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"NAME  READY STATUS\n"+
+		"\x1b[1mweb-1\x1b[0m \x1b[1m  1/1\x1b[0m \x1b[1mRunning\x1b[0m\n"+
+		"\x1b[1mdb-0 \x1b[0m \x1b[1m  0/1\x1b[0m \x1b[1mPending\x1b[0m\n"+
+		"\x1b[1mcache\x1b[0m \x1b[1m  1/1\x1b[0m \x1b[1mOK\x1b[0m\n" {
+		t.Errorf("Unexpected output\n%q", result)
+	}
+}
+
+func TestTablePrintFormatsOnce(t *testing.T) {
+	fetched, formatted := 0, 0
+	tv := &Table{
+		Output: Output{Writer: io.Discard},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name", Aggregate: AggregateCount,
+				Fetcher: func(col Column, row map[string]interface{}) interface{} {
+					fetched++
+					return row[col.Field]
+				},
+				Formatter: func(class string, data interface{}, formatter FormatterFunc) string {
+					if strings.HasPrefix(class, "table:row:") {
+						formatted++
+					}
+					return formatter(class, data, nil)
+				},
+			},
+			Column{Title: "Size", Field: "size", Width: -100},
+		},
+		GroupBy:     &Column{Field: "size"},
+		GroupTotals: true,
+		Layout:      LayoutAuto,
+	}
+	data := []map[string]interface{}{
+		{"name": "foo", "size": 1},
+		{"name": "bar", "size": 2},
+		{"name": "baz", "size": 1},
+	}
+	tv.Print(data)
+	if fetched != 3 || formatted != 3 {
+		t.Errorf("Cells fetched %d and formatted %d times instead of 3", fetched, formatted)
+	}
+
+	// sorting uses the fetched values
+	fetched, formatted = 0, 0
+	buf := new(bytes.Buffer)
+	tv.Writer = buf
+	tv.GroupBy = nil
+	tv.Columns[0].Aggregate = nil
+	tv.SortBy = ParseSortKeys("size,-name")
+	tv.Print(data)
+	if fetched != 3 || formatted != 3 {
+		t.Errorf("Sorted cells fetched %d and formatted %d times instead of 3", fetched, formatted)
+	}
+	if result := buf.String(); !strings.Contains(result, "foo") || strings.Index(result, "foo") > strings.Index(result, "baz") ||
+		strings.Index(result, "baz") > strings.Index(result, "bar") {
+		t.Errorf("Unexpected order\n%v", result)
+	}
+}
+
+func benchmarkRows(n int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		rows[i] = map[string]interface{}{
+			"id":    i,
+			"name":  "item " + strings.Repeat("x", i%20),
+			"size":  float64(i) * 1.5,
+			"state": "active",
+		}
+	}
+	return rows
+}
+
+func benchmarkTable() *Table {
+	return &Table{
+		Output: Output{Writer: io.Discard},
+		Columns: []Column{
+			Column{Title: "ID", Field: "id", Align: AlignRight},
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Size", Field: "size", Align: AlignRight, Aggregate: AggregateSum},
+			Column{Title: "State", Field: "state", Width: -100},
+		},
+		MaxWidth: 80,
+	}
+}
+
+func BenchmarkTablePrint(b *testing.B) {
+	tv := benchmarkTable()
+	rows := benchmarkRows(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tv.Print(rows)
+	}
+}
+
+func BenchmarkTablePrintGrouped(b *testing.B) {
+	tv := benchmarkTable()
+	tv.GroupBy = &Column{Title: "State", Field: "state"}
+	tv.GroupTotals = true
+	rows := benchmarkRows(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tv.Print(rows)
+	}
+}
+
+func BenchmarkTableStream(b *testing.B) {
+	tv := benchmarkTable()
+	rows := benchmarkRows(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := tv.Stream()
+		for _, row := range rows {
+			s.Push(row)
+		}
+		s.Close()
+	}
+}
+
+var concurrentTable = &Table{
+	Columns: []Column{
+		Column{Title: "Name", Field: "name"},
+		Column{Title: "Size", Field: "size", Align: AlignRight, Aggregate: AggregateSum},
+		Column{Title: "Note", Field: "note", Width: -100},
+	},
+	Border:   TestBorder,
+	MaxWidth: 30,
+	SortBy:   []SortKey{{Field: "size"}},
+}
+
+func TestTablePrintConcurrent(t *testing.T) {
+	data := []map[string]interface{}{
+		{"name": "foo", "size": 20, "note": Span{Value: "spanned"}},
+		{"name": "bar", "size": 1, "note": "short"},
+	}
+	expected := new(bytes.Buffer)
+	concurrentTable.Render(expected, data)
+
+	results := make(chan string)
+	for i := 0; i < 8; i++ {
+		go func() {
+			buf := new(bytes.Buffer)
+			concurrentTable.Render(buf, data)
+			results <- buf.String()
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if result := <-results; result != expected.String() {
+			t.Errorf("Unexpected output\n%v", result)
+		}
+	}
+}
+
+// failWriter fails after writing n bytes.
+type failWriter struct {
+	n      int
+	writes int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrClosedPipe
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestTableRender(t *testing.T) {
+	tv := &Table{
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+		},
+		Border: TestBorder,
+	}
+	data := []map[string]interface{}{{"name": "foo"}, {"name": "bar"}}
+	buf := new(bytes.Buffer)
+	n, err := tv.Render(buf, data)
+	if err != nil || n != int64(buf.Len()) || buf.Len() == 0 {
+		t.Errorf("Render returned %d, %v for %d bytes", n, err, buf.Len())
+	}
+
+	w := &failWriter{n: 10}
+	n, err = tv.Render(w, data)
+	if err != io.ErrClosedPipe || n != 10 {
+		t.Errorf("Render returned %d, %v instead of 10, %v", n, err, io.ErrClosedPipe)
+	}
+	if w.writes != 2 {
+		t.Errorf("Render didn't stop after the write error: %d writes", w.writes)
+	}
+
+	if _, err := tv.Render(buf, 42); err == nil {
+		t.Errorf("Render should fail for invalid data")
+	}
+
+	s := (&Table{Output: Output{Writer: &failWriter{n: 10}}, Columns: tv.Columns, Border: TestBorder}).Stream()
+	if err := s.Push(data[0]); err != nil {
+		t.Errorf("Push failed before printing: %v", err)
+	}
+	if err := s.Close(); err != io.ErrClosedPipe {
+		t.Errorf("Close returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTableValidate(t *testing.T) {
+	tests := []struct {
+		table Table
+		valid bool
+	}{
+		{Table{}, true},
+		{Table{Border: BorderASCII}, true},
+		{Table{Border: TestBorder + "v^"}, true},
+		{Table{Border: "+-+"}, false},
+		{Table{Border: "+-+", Plain: true}, true},
+		{Table{Columns: []Column{{Width: -60}, {Width: -40}}}, true},
+		{Table{Columns: []Column{{Width: -60}, {Width: -50}}}, false},
+		{Table{Columns: []Column{{MinWidth: -1}}}, false},
+		{Table{Layout: 3}, false},
+	}
+	for i, test := range tests {
+		if err := test.table.Validate(); (err == nil) != test.valid {
+			t.Errorf("Unexpected result of test %d: %v", i, err)
+		}
+	}
+	if _, err := tests[3].table.Render(io.Discard, nil); err == nil {
+		t.Errorf("Render should fail for an invalid border")
+	}
+
+	// Print and Sprint keep printing invalid configurations
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "A", Field: "a", Width: -60},
+			Column{Title: "B", Field: "b", Width: -50},
+		},
+		MaxWidth: 13,
+		Border:   "+-++|||+-++",
+	}
+	data := []map[string]interface{}{{"a": "x", "b": "y"}}
+	if err := tv.Print(data); err != nil {
+		t.Errorf("Print failed: %v", err)
+	}
+	expected := "" +
+		"+------+-----+\n" +
+		"|A     |B    |\n" +
+		"|x     |y    |\n" +
+		"+------+-----+\n"
+	if result := buf.String(); result != expected {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	if result := tv.Sprint(data); result != expected {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	tv.Border = "+-+"
+	if result := tv.Sprint(data); result != ""+
+		"╔══════╤═════╗\n"+
+		"║A     │B    ║\n"+
+		"╠══════╪═════╣\n"+
+		"║x     │y    ║\n"+
+		"╚══════╧═════╝\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	if _, err := tv.Render(buf, data); err == nil {
+		t.Errorf("Render should fail for an invalid border")
+	}
+}
+
+func TestTableSprint(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+		},
+		Border: TestBorder,
+	}
+	result := tv.Sprint([]map[string]interface{}{{"name": "foo"}})
+	if buf.Len() != 0 || tv.Writer != buf {
+		t.Errorf("Sprint wrote to Output.Writer")
+	}
+	if result != ""+
+		"+----+\n"+
+		"|Name|\n"+
+		"+----+\n"+
+		"|foo |\n"+
+		"+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	tv.Columns[0].Width = -100
+	tv.Terminal = func(w io.Writer) (int, bool) { return 10, true }
+	if result := tv.Sprint([]map[string]interface{}{{"name": "foo"}}); result != ""+
+		"+--------+\n"+
+		"|Name    |\n"+
+		"+--------+\n"+
+		"|foo     |\n"+
+		"+--------+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
@@ -0,0 +1,53 @@
+package cliview
+
+import (
+	"io"
+	"strconv"
+)
+
+// TerminalFunc reports whether w writes to a terminal and the width of the
+// terminal in columns. The width is 0 if it cannot be determined.
+type TerminalFunc func(w io.Writer) (width int, isTerm bool)
+
+// fileDescriptor is implemented by writers to files, e.g. *os.File.
+type fileDescriptor interface {
+	Fd() uintptr
+}
+
+// DetectTerminal is the default TerminalFunc. It queries the terminal size
+// of writers with a file descriptor. The width is 0 when the terminal does
+// not report its size, or the platform can't query it.
+func DetectTerminal(w io.Writer) (int, bool) {
+	f, ok := w.(fileDescriptor)
+	if !ok {
+		return 0, false
+	}
+	return terminalSize(f)
+}
+
+// terminal detects the terminal, falling back to $COLUMNS when its width
+// is unknown.
+func (o *Output) terminal(w io.Writer) (int, bool) {
+	detect := o.Terminal
+	if detect == nil {
+		detect = DetectTerminal
+	}
+	width, isTerm := detect(w)
+	if isTerm && width <= 0 {
+		width, _ = strconv.Atoi(o.getenv("COLUMNS"))
+	}
+	return width, isTerm
+}
+
+// TerminalWidth returns the width of the terminal the output is written to,
+// or DefaultWidth if the output is not a terminal.
+func (o *Output) TerminalWidth() int {
+	return o.terminalWidth(o.Out())
+}
+
+func (o *Output) terminalWidth(w io.Writer) int {
+	if width, isTerm := o.terminal(w); isTerm && width > 0 {
+		return width
+	}
+	return o.DefaultWidth
+}
@@ -0,0 +1,21 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package cliview
+
+import "os"
+
+// terminalSize can't query the terminal size on this platform. A file which
+// is a character device, like a console, is taken for a terminal of unknown
+// width.
+func terminalSize(f fileDescriptor) (int, bool) {
+	file, ok := f.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		return 0, false
+	}
+	return 0, fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,38 @@
+package cliview
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDetectTerminalNonFile(t *testing.T) {
+	if _, isTerm := DetectTerminal(new(bytes.Buffer)); isTerm {
+		t.Errorf("bytes.Buffer detected as terminal")
+	}
+}
+
+func TestOutputTerminalWidth(t *testing.T) {
+	o := &Output{Writer: new(bytes.Buffer)}
+	if w := o.TerminalWidth(); w != 0 {
+		t.Errorf("Unexpected width %d", w)
+	}
+	o.Terminal = func(w io.Writer) (int, bool) { return 120, true }
+	if w := o.TerminalWidth(); w != 120 {
+		t.Errorf("Unexpected width %d", w)
+	}
+	o.Terminal = func(w io.Writer) (int, bool) { return 0, true }
+	o.Getenv = func(key string) string { return "" }
+	if w := o.TerminalWidth(); w != 0 {
+		t.Errorf("Unexpected width %d", w)
+	}
+	o.Getenv = func(key string) string {
+		if key == "COLUMNS" {
+			return "100"
+		}
+		return ""
+	}
+	if w := o.TerminalWidth(); w != 100 {
+		t.Errorf("Unexpected width %d", w)
+	}
+}
@@ -0,0 +1,205 @@
+package cliview
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Color is a terminal color: one of the 16 standard colors, an index in the
+// 256-color palette or a 24-bit RGB color. The zero Color is the default
+// color of the terminal.
+type Color uint32
+
+// kinds of colors in the top byte of a Color
+const (
+	color16  Color = 1 << 24
+	color256 Color = 2 << 24
+	colorRGB Color = 3 << 24
+)
+
+// The 16 standard colors
+const (
+	ColorBlack Color = color16 + iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+	ColorBrightBlack
+	ColorBrightRed
+	ColorBrightGreen
+	ColorBrightYellow
+	ColorBrightBlue
+	ColorBrightMagenta
+	ColorBrightCyan
+	ColorBrightWhite
+)
+
+// Color256 returns the color with index n in the 256-color palette.
+func Color256(n uint8) Color {
+	return color256 | Color(n)
+}
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// sgr returns the SGR parameters selecting the color as foreground color, or
+// as background color if bg is set.
+func (c Color) sgr(bg bool) string {
+	n := int(c & 0xffffff)
+	base := 30
+	if bg {
+		base = 40
+	}
+	switch c &^ 0xffffff {
+	case color16:
+		if n >= 8 {
+			base += 60 - 8
+		}
+		return strconv.Itoa(base + n)
+	case color256:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(n)
+	case colorRGB:
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(n>>16) + ";" + strconv.Itoa(n>>8&0xff) + ";" + strconv.Itoa(n&0xff)
+	}
+	return ""
+}
+
+// Style is the appearance of styled text.
+type Style struct {
+	Fg, Bg    Color
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+}
+
+// SGR returns the parameters of the ANSI SGR escape sequence for the style,
+// e.g. "1;31" for bold red, or "" for the plain style.
+func (s Style) SGR() string {
+	params := make([]string, 0, 6)
+	for _, attr := range []struct {
+		set   bool
+		param string
+	}{
+		{s.Bold, "1"},
+		{s.Dim, "2"},
+		{s.Italic, "3"},
+		{s.Underline, "4"},
+		{s.Fg != 0, s.Fg.sgr(false)},
+		{s.Bg != 0, s.Bg.sgr(true)},
+	} {
+		if attr.set && attr.param != "" {
+			params = append(params, attr.param)
+		}
+	}
+	return strings.Join(params, ";")
+}
+
+// Apply returns text in the style, wrapped in ANSI escape sequences.
+func (s Style) Apply(text string) string {
+	sgr := s.SGR()
+	if sgr == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + sgr + "m" + text + "\x1b[0m"
+}
+
+// Theme maps styling classes to styles. The keys are patterns matching
+// classes:
+//
+//	"table:head:name"     exact: only this class
+//	"tree:val:*/status"   glob: '*' matches any text, including ':' and '/'
+//	"table:head:"         prefix: a pattern ending with ':' or '/' matches
+//	                      all classes starting with it
+//
+// An exact pattern takes precedence over globs, which take precedence over
+// prefixes. Of several matching globs or prefixes the longest one wins.
+type Theme map[string]Style
+
+// Styler compiles the theme into a StylerFunc to be used as Output.Styler or
+// Column.Styler. Changes to the theme after compiling are not reflected.
+func (t Theme) Styler() StylerFunc {
+	styles := make(map[string]Style, len(t))
+	var globs, prefixes []string
+	for pattern, style := range t {
+		styles[pattern] = style
+		if strings.Contains(pattern, "*") {
+			globs = append(globs, pattern)
+		} else if strings.HasSuffix(pattern, ":") || strings.HasSuffix(pattern, "/") {
+			prefixes = append(prefixes, pattern)
+		}
+	}
+	for _, patterns := range [][]string{globs, prefixes} {
+		sort.Slice(patterns, func(i, j int) bool {
+			if len(patterns[i]) != len(patterns[j]) {
+				return len(patterns[i]) > len(patterns[j])
+			}
+			return patterns[i] < patterns[j]
+		})
+	}
+
+	return func(class, text string, data interface{}) string {
+		if style, ok := styles[class]; ok {
+			return style.Apply(text)
+		}
+		for _, pattern := range globs {
+			if matchGlob(pattern, class) {
+				return styles[pattern].Apply(text)
+			}
+		}
+		for _, pattern := range prefixes {
+			if strings.HasPrefix(class, pattern) {
+				return styles[pattern].Apply(text)
+			}
+		}
+		return text
+	}
+}
+
+// matchGlob tells if s matches pattern, where '*' matches any text.
+func matchGlob(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return len(s) >= len(last) && strings.HasSuffix(s, last)
+}
+
+var (
+	// ThemeDark is a theme for terminals with a dark background.
+	ThemeDark = Theme{
+		"table:head:":     {Fg: ColorBrightCyan, Bold: true},
+		"table:colgroup:": {Fg: ColorBrightCyan, Bold: true},
+		"table:group:":    {Fg: ColorBrightYellow, Bold: true},
+		"table:subtotal:": {Dim: true},
+		"table:foot:":     {Bold: true},
+		"table:record:":   {Dim: true},
+		"tree:key:":       {Fg: ColorBrightBlue, Bold: true},
+	}
+
+	// ThemeLight is a theme for terminals with a light background.
+	ThemeLight = Theme{
+		"table:head:":     {Fg: ColorBlue, Bold: true},
+		"table:colgroup:": {Fg: ColorBlue, Bold: true},
+		"table:group:":    {Fg: ColorMagenta, Bold: true},
+		"table:subtotal:": {Fg: ColorBrightBlack},
+		"table:foot:":     {Bold: true},
+		"table:record:":   {Fg: ColorBrightBlack},
+		"tree:key:":       {Fg: ColorBlue, Bold: true},
+	}
+)
@@ -0,0 +1,104 @@
+package cliview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStyleApply(t *testing.T) {
+	tests := []struct {
+		style  Style
+		result string
+	}{
+		{Style{}, "text"},
+		{Style{Bold: true, Fg: ColorRed}, "\x1b[1;31mtext\x1b[0m"},
+		{Style{Fg: ColorBrightWhite, Bg: ColorBlue}, "\x1b[97;44mtext\x1b[0m"},
+		{Style{Dim: true, Italic: true, Underline: true}, "\x1b[2;3;4mtext\x1b[0m"},
+		{Style{Fg: Color256(208), Bg: Color256(0)}, "\x1b[38;5;208;48;5;0mtext\x1b[0m"},
+		{Style{Fg: RGB(255, 128, 0), Bg: RGB(0, 0, 1)}, "\x1b[38;2;255;128;0;48;2;0;0;1mtext\x1b[0m"},
+	}
+	for _, test := range tests {
+		if result := test.style.Apply("text"); result != test.result {
+			t.Errorf("Unexpected result %q instead of %q", result, test.result)
+		}
+	}
+}
+
+func TestThemeStyler(t *testing.T) {
+	bold := Style{Bold: true}
+	styler := Theme{
+		"table:head:":           {Fg: ColorRed},
+		"table:head:name":       bold,
+		"tree:val:":             {Fg: ColorGreen},
+		"tree:val:config/":      {Fg: ColorYellow},
+		"tree:val:*/status":     {Fg: ColorBlue},
+		"tree:val:*/*/status":   {Fg: ColorCyan},
+		"tree:val:items/*/size": {Dim: true},
+	}.Styler()
+	tests := []struct {
+		class  string
+		result string
+	}{
+		{"table:head:name", bold.Apply("x")},
+		{"table:head:size", Style{Fg: ColorRed}.Apply("x")},
+		{"table:row:name", "x"},
+		{"tree:val:name", Style{Fg: ColorGreen}.Apply("x")},
+		{"tree:val:config/port", Style{Fg: ColorYellow}.Apply("x")},
+		{"tree:val:config/status", Style{Fg: ColorBlue}.Apply("x")},
+		{"tree:val:pods/0/status", Style{Fg: ColorCyan}.Apply("x")},
+		{"tree:val:items/0/size", Style{Dim: true}.Apply("x")},
+		{"tree:val:items/0/sizes", Style{Fg: ColorGreen}.Apply("x")},
+	}
+	for _, test := range tests {
+		if result := styler(test.class, "x", nil); result != test.result {
+			t.Errorf("Unexpected result %q for %q instead of %q", result, test.class, test.result)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		match      bool
+	}{
+		{"a*", "a", true},
+		{"a*", "abc", true},
+		{"*c", "abc", true},
+		{"a*c", "ac", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "axbxc", true},
+		{"*a*a", "a", false},
+		{"*a*a", "aa", true},
+		{"a*c", "ab", false},
+	}
+	for _, test := range tests {
+		if match := matchGlob(test.pattern, test.s); match != test.match {
+			t.Errorf("matchGlob(%q, %q) = %v", test.pattern, test.s, match)
+		}
+	}
+}
+
+func TestThemeTable(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer:    buf,
+			Styler:    Theme{"table:head:": {Bold: true}}.Styler(),
+			ColorMode: ColorModeAlways,
+		},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{{"name": "foo"}})
+	result := buf.String()
+	if result != ""+
+		"+----+\n"+
+		"|\x1b[1mName\x1b[0m|\n"+
+		"+----+\n"+
+		"|foo |\n"+
+		"+----+\n" {
+		t.Errorf("Unexpected output\n%q", result)
+	}
+}
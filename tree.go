@@ -1,9 +1,12 @@
 package cliview
 
 import (
+	"bytes"
+	"encoding"
 	"fmt"
 	"io"
 	"math"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
@@ -53,6 +56,8 @@ func SuffixArrayKeyRanker(dict map[string][]string) KeyRankFunc {
 	}
 }
 
+// Tree prints data as a tree of keys and values. Like Table, it can be
+// printed from multiple goroutines concurrently as long as it isn't modified.
 type Tree struct {
 	Output
 	Indent    int
@@ -63,8 +68,26 @@ func NewTree() *Tree {
 	return &Tree{Indent: DefaultIndent}
 }
 
-func (tv *Tree) Print(obj interface{}) {
-	tv.render(obj, "", tv.Out(), tv.Padding, false, false)
+// Print prints obj to Output.Writer and returns the first write error.
+func (tv *Tree) Print(obj interface{}) error {
+	_, err := tv.Render(tv.Out(), obj)
+	return err
+}
+
+// Sprint returns obj printed like Print as a string, without writing to
+// Output.Writer.
+func (tv *Tree) Sprint(obj interface{}) string {
+	buf := new(bytes.Buffer)
+	tv.Render(buf, obj)
+	return buf.String()
+}
+
+// Render prints obj to w and returns the number of bytes written. It stops
+// at the first write error, which is returned.
+func (tv *Tree) Render(w io.Writer, obj interface{}) (int64, error) {
+	r := &treeRender{Tree: tv, w: &errWriter{w: w}, colors: tv.colorLevel(w), visiting: make(map[treeRef]bool)}
+	r.render(obj, "", tv.Padding, false, false)
+	return r.w.n, r.w.err
 }
 
 func (tv *Tree) RankKey(path, key string) uint {
@@ -100,102 +123,277 @@ func (s *keySorter) Less(i, j int) bool {
 	return s.keys[i].rank < s.keys[j].rank
 }
 
-func (tv *Tree) render(obj interface{}, path string, w io.Writer, padding int, skipPadding, forCntr bool) {
+type treeEntry struct {
+	key string
+	val interface{}
+}
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// isTextType reports whether values of t describe themselves as text.
+func isTextType(t reflect.Type) bool {
+	return t.Implements(errorType) || t.Implements(stringerType) || t.Implements(textMarshalerType)
+}
+
+// isTreeScalar reports whether v is printed as a single value rather than
+// walked into.
+func isTreeScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Array:
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+	default:
+		return true
+	}
+	return v.CanInterface() && (isTextType(v.Type()) || isTextType(reflect.PtrTo(v.Type())))
+}
+
+// scalarInterface returns the scalar v as an interface, as a pointer if the
+// methods describing it as text have a pointer receiver.
+func scalarInterface(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() || isTextType(v.Type()) || !isTextType(reflect.PtrTo(v.Type())) {
+		return valueInterface(v)
+	}
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface()
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func (tv *Tree) keyVisible(path, key string) bool {
+	return tv.Format("tree:key:"+path, key) != ""
+}
+
+// entries lists the visible keys of a map or struct value, sorted by rank.
+// Map keys with the same rank are sorted by name, struct fields keep their
+// declaration order.
+func (tv *Tree) entries(v reflect.Value, path string) []treeEntry {
+	var entries []treeEntry
+	keys := &keySorter{}
+	if v.Kind() == reflect.Map {
+		values := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			var key string
+			if k.Kind() == reflect.String {
+				key = k.String()
+			} else {
+				key = fmt.Sprintf("%v", k.Interface())
+			}
+			if tv.keyVisible(path, key) {
+				values[key] = valueInterface(v.MapIndex(k))
+				keys.keys = append(keys.keys, &keyRank{key: key, rank: tv.RankKey(path, key)})
+			}
+		}
+		sort.Sort(keys)
+		for _, kr := range keys.keys {
+			entries = append(entries, treeEntry{key: kr.key, val: values[kr.key]})
+		}
+		return entries
+	}
+
+	values := make(map[string]interface{})
+	for _, f := range structFields(v.Type()) {
+		if f.hidden {
+			continue
+		}
+		fv := fieldValue(v, f.index)
+		if !fv.IsValid() || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		if tv.keyVisible(path, f.key) {
+			values[f.key] = valueInterface(fv)
+			keys.keys = append(keys.keys, &keyRank{key: f.key, rank: tv.RankKey(path, f.key)})
+		}
+	}
+	sort.Stable(&rankSorter{keys})
+	for _, kr := range keys.keys {
+		entries = append(entries, treeEntry{key: kr.key, val: values[kr.key]})
+	}
+	return entries
+}
+
+// rankSorter orders keys by rank only.
+type rankSorter struct {
+	*keySorter
+}
+
+func (s *rankSorter) Less(i, j int) bool {
+	return s.keys[i].rank < s.keys[j].rank
+}
+
+// treeRender is the state of printing a tree.
+type treeRender struct {
+	*Tree
+	w        *errWriter
+	colors   int              // color level of the output
+	visiting map[treeRef]bool // references on the path being printed
+}
+
+// treeRef identifies a pointer, map or slice.
+type treeRef struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// enter marks the pointers, maps and slices referenced by v, following
+// pointers and interfaces, as being printed. It returns false if one of them
+// already is, i.e. v contains itself, in which case nothing is marked.
+func (tv *treeRender) enter(v reflect.Value) ([]treeRef, bool) {
+	var refs []treeRef
+	for v.IsValid() {
+		var ref treeRef
+		switch v.Kind() {
+		case reflect.Interface:
+			v = v.Elem()
+			continue
+		case reflect.Ptr, reflect.Map, reflect.Slice:
+			if v.IsNil() {
+				return refs, true
+			}
+			ref = treeRef{ptr: v.Pointer(), typ: v.Type()}
+			if v.Kind() == reflect.Slice {
+				ref.len = v.Len()
+			}
+		default:
+			return refs, true
+		}
+		if tv.visiting[ref] {
+			tv.leave(refs)
+			return nil, false
+		}
+		tv.visiting[ref] = true
+		refs = append(refs, ref)
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		v = v.Elem()
+	}
+	return refs, true
+}
+
+func (tv *treeRender) leave(refs []treeRef) {
+	for _, ref := range refs {
+		delete(tv.visiting, ref)
+	}
+}
+
+func (tv *treeRender) style(class, text string, data interface{}) string {
+	return tv.Tree.style(tv.colors, class, text, data, nil)
+}
+
+func (tv *treeRender) render(obj interface{}, path string, padding int, skipPadding, forCntr bool) {
+	w := tv.w
+	if w.err != nil {
+		return
+	}
 	padBuf := PaddingBuffer(padding)
 	padStr := padBuf.String()
 	empty := false
-	switch obj.(type) {
-	case map[string]interface{}:
-		if mapObj := obj.(map[string]interface{}); len(mapObj) == 0 {
+	refs, ok := tv.enter(reflect.ValueOf(obj))
+	if !ok {
+		// a value containing itself is not walked again
+		if skipPadding {
+			padStr = ""
+		}
+		fmt.Fprintln(w, padStr+tv.style("tree:val:"+path, "<cycle>", obj))
+		return
+	}
+	defer tv.leave(refs)
+	v := indirectValue(reflect.ValueOf(obj))
+	switch {
+	case !v.IsValid():
+		empty = true
+	case isTreeScalar(v):
+		if skipPadding {
+			padStr = ""
+		}
+		class := "tree:val:" + path
+		val := scalarInterface(v)
+		fmt.Fprintln(w, padStr+tv.style(class, tv.Format(class, val), val))
+	case v.Kind() == reflect.Map || v.Kind() == reflect.Struct:
+		if v.Kind() == reflect.Map && v.Len() == 0 {
 			empty = true
-		} else {
-			keys := &keySorter{keys: make([]*keyRank, 0, len(mapObj))}
-			for k := range mapObj {
-				if tv.Format("tree:key:"+path, k) != "" {
-					rank := tv.RankKey(path, k)
-					keys.keys = append(keys.keys, &keyRank{key: k, rank: rank})
-				}
-			}
-			sort.Sort(keys)
-			if skipPadding && !forCntr {
-				fmt.Fprintln(w, "")
-				skipPadding = false
-			}
-			for _, kr := range keys.keys {
-				v := mapObj[kr.key]
-				keyStr := tv.Styling("tree:key:"+path, kr.key, v, nil)
-				if skipPadding {
-					fmt.Fprintf(w, "%s: ", keyStr)
-					skipPadding = false
-				} else {
-					fmt.Fprintf(w, padStr+"%s: ", keyStr)
-				}
-				subpath := path
-				if len(path) > 0 {
-					subpath += "/"
-				}
-				subpath += kr.key
-				tv.render(v, subpath, w, padding+tv.Indent, true, false)
-			}
+			break
 		}
-	case map[interface{}]interface{}:
-		converted := make(map[string]interface{})
-		for k, v := range obj.(map[interface{}]interface{}) {
-			converted[fmt.Sprintf("%v", k)] = v
-		}
-		tv.render(converted, path, w, padding, skipPadding, forCntr)
-	case []map[string]interface{}:
-		data := obj.([]map[string]interface{})
-		converted := make([]interface{}, len(data))
-		for i, v := range data {
-			converted[i] = v
-		}
-		tv.render(converted, path, w, padding, skipPadding, forCntr)
-	case []map[interface{}]interface{}:
-		data := obj.([]map[interface{}]interface{})
-		converted := make([]interface{}, len(data))
-		for i, v := range data {
-			converted[i] = v
-		}
-		tv.render(converted, path, w, padding, skipPadding, forCntr)
-	case []interface{}:
-		if arrObj := obj.([]interface{}); len(arrObj) == 0 {
+		entries := tv.entries(v, path)
+		if v.Kind() == reflect.Struct && len(entries) == 0 {
 			empty = true
-		} else {
+			break
+		}
+		if skipPadding && !forCntr {
+			fmt.Fprintln(w, "")
+			skipPadding = false
+		}
+		for _, e := range entries {
+			keyStr := tv.style("tree:key:"+path, e.key, e.val)
 			if skipPadding {
-				fmt.Fprintln(w, "")
-				if forCntr {
-					padding += tv.Indent
-					for i := 0; i < tv.Indent; i++ {
-						padBuf.WriteString(" ")
-					}
-					padStr = padBuf.String()
-				}
-			}
-			if len(padStr) >= tv.Padding+2 {
-				padStr = padStr[0:len(padStr)-2] + "- "
+				fmt.Fprintf(w, "%s: ", keyStr)
+				skipPadding = false
 			} else {
-				padStr = PaddingBuffer(tv.Padding+tv.Indent-2).String() + "- "
-				padding = tv.Padding + tv.Indent
+				fmt.Fprintf(w, padStr+"%s: ", keyStr)
 			}
-			for i, v := range arrObj {
-				fmt.Fprintf(w, padStr)
-				subpath := fmt.Sprintf("%v", i)
-				if len(path) > 0 {
-					subpath = path + "/" + subpath
-				}
-				tv.render(v, subpath, w, padding, true, true)
+			subpath := path
+			if len(path) > 0 {
+				subpath += "/"
 			}
+			subpath += e.key
+			tv.render(e.val, subpath, padding+tv.Indent, true, false)
 		}
 	default:
-		if obj == nil {
+		if v.Len() == 0 {
 			empty = true
+			break
+		}
+		if skipPadding {
+			fmt.Fprintln(w, "")
+			if forCntr {
+				padding += tv.Indent
+				for i := 0; i < tv.Indent; i++ {
+					padBuf.WriteString(" ")
+				}
+				padStr = padBuf.String()
+			}
+		}
+		if len(padStr) >= tv.Padding+2 {
+			padStr = padStr[0:len(padStr)-2] + "- "
 		} else {
-			if skipPadding {
-				padStr = ""
+			padStr = PaddingBuffer(tv.Padding+tv.Indent-2).String() + "- "
+			padding = tv.Padding + tv.Indent
+		}
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprint(w, padStr)
+			subpath := fmt.Sprintf("%v", i)
+			if len(path) > 0 {
+				subpath = path + "/" + subpath
 			}
-			class := "tree:val:" + path
-			fmt.Fprintln(w, padStr+tv.Styling(class, tv.Format(class, obj), obj, nil))
+			tv.render(valueInterface(v.Index(i)), subpath, padding, true, true)
 		}
 	}
 
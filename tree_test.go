@@ -3,6 +3,7 @@ package cliview
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"testing"
 )
 
@@ -382,3 +383,234 @@ func TestTreePatternArrayKeyRanker(t *testing.T) {
 		t.Errorf("Unexpected output\n%v", result)
 	}
 }
+
+type testTreeMeta struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type testTreeObject struct {
+	testTreeMeta
+	Name     string         `json:"name"`
+	Ports    []int          `json:"ports"`
+	Owner    *testTreeOwner `json:"owner,omitempty"`
+	Parent   *testTreeOwner `json:"parent"`
+	Counts   map[int]string `json:"counts"`
+	Internal string         `json:"-"`
+	Note     string         `json:"note,omitempty"`
+	private  string
+}
+
+type testTreeOwner struct {
+	Name string
+}
+
+func TestTreePrintStruct(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Tree{
+		Output: Output{Writer: buf},
+		Indent: DefaultIndent,
+		KeyRanker: func(path, key string) int {
+			if key == "name" {
+				return 0
+			}
+			return -1
+		},
+	}
+	tv.Print(&testTreeObject{
+		testTreeMeta: testTreeMeta{Labels: map[string]string{"b": "2", "a": "1"}},
+		Name:         "server",
+		Ports:        []int{80, 443},
+		Owner:        &testTreeOwner{Name: "Jack"},
+		Counts:       map[int]string{10: "ten", 2: "two"},
+		Internal:     "x",
+		private:      "y",
+	})
+	result := buf.String()
+	if result != ""+
+		"name: server\n"+
+		"labels: \n"+
+		"    a: 1\n"+
+		"    b: 2\n"+
+		"ports: \n"+
+		"  - 80\n"+
+		"  - 443\n"+
+		"owner: \n"+
+		"    Name: Jack\n"+
+		"parent: \n"+
+		"counts: \n"+
+		"    10: ten\n"+
+		"    2: two\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTreePrintTypedValues(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Tree{
+		Output: Output{
+			Writer: buf,
+			Styler: func(class, text string, data interface{}) string {
+				return "<" + class + ">" + text
+			},
+		},
+		Indent: DefaultIndent,
+	}
+	name := "Alice"
+	tv.Print(map[string][]*string{
+		"names": []*string{&name, nil},
+	})
+	tv.Print([2]string{"a", "b"})
+	result := buf.String()
+	if result != ""+
+		"<tree:key:>names: \n"+
+		"  - <tree:val:names/0>Alice\n"+
+		"  - \n"+
+		"  - <tree:val:0>a\n"+
+		"  - <tree:val:1>b\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+var concurrentTree = &Tree{
+	Indent:    DefaultIndent,
+	KeyRanker: ArrayKeyRanker([]string{"name"}),
+}
+
+func TestTreePrintConcurrent(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "foo",
+		"items": []interface{}{1, map[string]interface{}{"a": true}},
+	}
+	expected := concurrentTree.Sprint(data)
+
+	results := make(chan string)
+	for i := 0; i < 8; i++ {
+		go func() {
+			results <- concurrentTree.Sprint(data)
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if result := <-results; result != expected {
+			t.Errorf("Unexpected output\n%v", result)
+		}
+	}
+}
+
+func TestTreeRender(t *testing.T) {
+	tv := NewTree()
+	data := map[string]interface{}{"a": 1, "b": []interface{}{2, 3}}
+	buf := new(bytes.Buffer)
+	n, err := tv.Render(buf, data)
+	if err != nil || n != int64(buf.Len()) || buf.String() != "a: 1\nb: \n  - 2\n  - 3\n" {
+		t.Errorf("Render returned %d, %v for\n%v", n, err, buf.String())
+	}
+
+	w := &failWriter{n: 3}
+	n, err = tv.Render(w, data)
+	if err != io.ErrClosedPipe || n != 3 || w.writes != 2 {
+		t.Errorf("Render returned %d, %v after %d writes", n, err, w.writes)
+	}
+	tv.Writer = &failWriter{n: 3}
+	if err := tv.Print(data); err != io.ErrClosedPipe {
+		t.Errorf("Print returned %v instead of %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestTreeSprint(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Tree{
+		Output: Output{Writer: buf},
+		Indent: DefaultIndent,
+	}
+	if result := tv.Sprint(map[string]interface{}{"key": "value"}); result != "key: value\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+	if buf.Len() != 0 || tv.Writer != buf {
+		t.Errorf("Sprint wrote to Output.Writer")
+	}
+}
+
+type testTreeVersion struct {
+	Major, Minor int
+}
+
+func (v *testTreeVersion) String() string {
+	return fmt.Sprintf("v%d.%d", v.Major, v.Minor)
+}
+
+type testTreeNode struct {
+	Name     string
+	Version  testTreeVersion
+	Next     *testTreeNode
+	Children []interface{}
+}
+
+func TestTreePrintPointerStringer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Tree{
+		Output: Output{Writer: buf},
+		Indent: DefaultIndent,
+	}
+	tv.Print(testTreeNode{Name: "a", Version: testTreeVersion{1, 2}})
+	tv.Print(map[string]testTreeVersion{"v": {3, 4}})
+	result := buf.String()
+	if result != ""+
+		"Name: a\n"+
+		"Version: v1.2\n"+
+		"Next: \n"+
+		"Children: \n"+
+		"v: v3.4\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTreePrintCycle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Tree{
+		Output: Output{Writer: buf},
+		Indent: DefaultIndent,
+	}
+	node := &testTreeNode{Name: "a"}
+	node.Next = node
+	node.Children = []interface{}{nil}
+	node.Children[0] = node.Children
+	m := map[string]interface{}{"name": "m"}
+	m["self"] = m
+	shared := &testTreeNode{Name: "b"}
+	tv.Print(node)
+	tv.Print(m)
+	tv.Print([]*testTreeNode{shared, shared})
+	result := buf.String()
+	if result != ""+
+		"Name: a\n"+
+		"Version: v0.0\n"+
+		"Next: <cycle>\n"+
+		"Children: \n"+
+		"  - <cycle>\n"+
+		"name: m\n"+
+		"self: <cycle>\n"+
+		"  - Name: b\n"+
+		"    Version: v0.0\n"+
+		"    Next: \n"+
+		"    Children: \n"+
+		"  - Name: b\n"+
+		"    Version: v0.0\n"+
+		"    Next: \n"+
+		"    Children: \n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTreePrintEmbeddingCycle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Tree{
+		Output: Output{Writer: buf},
+		Indent: DefaultIndent,
+	}
+	if err := tv.Print(testEmbedNode{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if result := buf.String(); result != "Name: a\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
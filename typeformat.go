@@ -0,0 +1,255 @@
+package cliview
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// TypeFormatters maps Go types to formatters. It is used by Output.Format
+// before the default formatting, which is passed to the registered
+// formatters as the next formatter in the chain.
+type TypeFormatters struct {
+	types      map[reflect.Type]FormatterFunc
+	interfaces []typeFormatter // in the order of registration
+	kinds      map[reflect.Kind]FormatterFunc
+}
+
+type typeFormatter struct {
+	typ reflect.Type
+	f   FormatterFunc
+}
+
+func NewTypeFormatters() *TypeFormatters {
+	return &TypeFormatters{types: make(map[reflect.Type]FormatterFunc)}
+}
+
+// Register registers f for values of the type of sample, only this type:
+// e.g. the sample 0 registers int but not int64, see RegisterKind. For an
+// interface, sample is a nil pointer to it, e.g. (*error)(nil), and f is
+// used for all types implementing it which have no formatter of their own.
+// Interfaces are tried in the order they are registered. A nil sample has
+// no type and registers nothing.
+func (tf *TypeFormatters) Register(sample interface{}, f FormatterFunc) *TypeFormatters {
+	if sample == nil {
+		return tf
+	}
+	if tf.types == nil {
+		tf.types = make(map[reflect.Type]FormatterFunc)
+	}
+	t := reflect.TypeOf(sample)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		t = t.Elem()
+		for i := range tf.interfaces {
+			if tf.interfaces[i].typ == t {
+				tf.interfaces[i].f = f
+				return tf
+			}
+		}
+		tf.interfaces = append(tf.interfaces, typeFormatter{typ: t, f: f})
+		return tf
+	}
+	tf.types[t] = f
+	return tf
+}
+
+// RegisterKind registers f for the types of kind, e.g. reflect.Int64 for
+// int64 and the types defined from it, which have no formatter registered
+// for them or an interface they implement.
+func (tf *TypeFormatters) RegisterKind(kind reflect.Kind, f FormatterFunc) *TypeFormatters {
+	if tf.kinds == nil {
+		tf.kinds = make(map[reflect.Kind]FormatterFunc)
+	}
+	tf.kinds[kind] = f
+	return tf
+}
+
+func (tf *TypeFormatters) lookup(data interface{}) FormatterFunc {
+	if data == nil {
+		return nil
+	}
+	t := reflect.TypeOf(data)
+	if f, ok := tf.types[t]; ok {
+		return f
+	}
+	for _, i := range tf.interfaces {
+		if t.Implements(i.typ) {
+			return i.f
+		}
+	}
+	return tf.kinds[t.Kind()]
+}
+
+// Format is a FormatterFunc formatting data with the formatter registered
+// for its type, passing formatter (the default formatter if nil) as the
+// next one. Without a registered formatter, formatter is used directly.
+func (tf *TypeFormatters) Format(class string, data interface{}, formatter FormatterFunc) string {
+	if formatter == nil {
+		formatter = defaultFormatter
+	}
+	if f := tf.lookup(data); f != nil {
+		return f(class, data, formatter)
+	}
+	return formatter(class, data, nil)
+}
+
+// TimeFormatter formats time.Time values with layout, the zero time as "".
+func TimeFormatter(layout string) FormatterFunc {
+	return func(class string, data interface{}, formatter FormatterFunc) string {
+		t, ok := data.(time.Time)
+		if !ok {
+			return formatter(class, data, nil)
+		}
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	}
+}
+
+// RelativeTimeFormatter formats time.Time values relative to now (time.Now if
+// nil) in the largest unit, e.g. "3m ago" or "in 2d".
+func RelativeTimeFormatter(now func() time.Time) FormatterFunc {
+	if now == nil {
+		now = time.Now
+	}
+	return func(class string, data interface{}, formatter FormatterFunc) string {
+		t, ok := data.(time.Time)
+		if !ok {
+			return formatter(class, data, nil)
+		}
+		if t.IsZero() {
+			return ""
+		}
+		d := now().Sub(t)
+		switch {
+		case d > -time.Second && d < time.Second:
+			return "now"
+		case d < 0:
+			return "in " + humanizeDuration(-d, 1)
+		}
+		return humanizeDuration(d, 1) + " ago"
+	}
+}
+
+// DurationFormatter formats time.Duration values in the two largest units,
+// e.g. "2d3h" or "5m10s".
+func DurationFormatter(class string, data interface{}, formatter FormatterFunc) string {
+	d, ok := data.(time.Duration)
+	if !ok {
+		return formatter(class, data, nil)
+	}
+	if d < 0 {
+		return "-" + humanizeDuration(-d, 2)
+	}
+	return humanizeDuration(d, 2)
+}
+
+// humanizeDuration formats d in at most units of days, hours, minutes and
+// seconds, leaving out the smaller units. Durations below a second are
+// formatted by time.Duration.
+func humanizeDuration(d time.Duration, units int) string {
+	if d < time.Second {
+		return d.String()
+	}
+	str := ""
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	} {
+		if units == 0 {
+			break
+		}
+		if n := d / unit.d; n > 0 || str != "" {
+			if n > 0 {
+				str += strconv.FormatInt(int64(n), 10) + unit.name
+			}
+			d -= n * unit.d
+			units--
+		}
+	}
+	return str
+}
+
+// HexFormatter formats []byte values as hexadecimal.
+func HexFormatter(class string, data interface{}, formatter FormatterFunc) string {
+	if b, ok := data.([]byte); ok {
+		return hex.EncodeToString(b)
+	}
+	return formatter(class, data, nil)
+}
+
+// Base64Formatter formats []byte values in standard base64 encoding.
+func Base64Formatter(class string, data interface{}, formatter FormatterFunc) string {
+	if b, ok := data.([]byte); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return formatter(class, data, nil)
+}
+
+// ErrorFormatter formats errors by their message.
+func ErrorFormatter(class string, data interface{}, formatter FormatterFunc) string {
+	if err, ok := data.(error); ok {
+		return err.Error()
+	}
+	return formatter(class, data, nil)
+}
+
+// StringerFormatter formats fmt.Stringer values by their String method.
+func StringerFormatter(class string, data interface{}, formatter FormatterFunc) string {
+	if s, ok := data.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return formatter(class, data, nil)
+}
+
+// ThousandsFormatter formats integers with sep between groups of thousands,
+// e.g. "1,234,567" with sep ",". Each integer type (or kind) it is used for
+// needs to be registered.
+func ThousandsFormatter(sep string) FormatterFunc {
+	return func(class string, data interface{}, formatter FormatterFunc) string {
+		var digits string
+		switch v := reflect.ValueOf(data); {
+		case data == nil:
+			return formatter(class, data, nil)
+		case isIntKind(v.Kind()):
+			digits = strconv.FormatInt(v.Int(), 10)
+		case isUintKind(v.Kind()):
+			digits = strconv.FormatUint(v.Uint(), 10)
+		default:
+			return formatter(class, data, nil)
+		}
+		sign := ""
+		if digits[0] == '-' {
+			sign, digits = "-", digits[1:]
+		}
+		str := digits[:(len(digits)-1)%3+1]
+		for i := len(str); i < len(digits); i += 3 {
+			str += sep + digits[i:i+3]
+		}
+		return sign + str
+	}
+}
+
+// BoolFormatter formats booleans as t or f, e.g. "yes" and "no" or "✓" and
+// "✗".
+func BoolFormatter(t, f string) FormatterFunc {
+	return func(class string, data interface{}, formatter FormatterFunc) string {
+		b, ok := data.(bool)
+		switch {
+		case !ok:
+			return formatter(class, data, nil)
+		case b:
+			return t
+		}
+		return f
+	}
+}
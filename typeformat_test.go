@@ -0,0 +1,128 @@
+package cliview
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testLevel int
+
+func (l testLevel) String() string {
+	return fmt.Sprintf("level-%d", int(l))
+}
+
+type testLevelError int
+
+func (e testLevelError) Error() string {
+	return "error"
+}
+
+func (e testLevelError) String() string {
+	return "stringer"
+}
+
+func TestTypeFormattersRegister(t *testing.T) {
+	tf := NewTypeFormatters().
+		Register(time.Duration(0), DurationFormatter).
+		Register((*error)(nil), ErrorFormatter).
+		Register((*fmt.Stringer)(nil), StringerFormatter).
+		Register(0, ThousandsFormatter(",")).
+		Register(nil, BoolFormatter("yes", "no")).
+		RegisterKind(reflect.Uint64, ThousandsFormatter("."))
+	for _, test := range []struct {
+		data     interface{}
+		expected string
+	}{
+		{nil, ""},
+		{"text", "text"},
+		{1234567, "1,234,567"},
+		{int64(1234567), "1234567"},
+		{uint64(1234567), "1.234.567"},
+		{true, "true"},
+		{90 * time.Minute, "1h30m"},
+		{testLevel(2), "level-2"},
+		{testLevelError(2), "error"},
+		{errors.New("failed"), "failed"},
+		{float32(1.5), "1.5"},
+	} {
+		if result := tf.Format("", test.data, nil); result != test.expected {
+			t.Errorf("Format(%#v) = %q, expected %q", test.data, result, test.expected)
+		}
+	}
+}
+
+func TestTypeFormatters(t *testing.T) {
+	now := time.Date(2020, 5, 10, 12, 0, 0, 0, time.UTC)
+	relative := RelativeTimeFormatter(func() time.Time { return now })
+	for _, test := range []struct {
+		formatter FormatterFunc
+		data      interface{}
+		expected  string
+	}{
+		{TimeFormatter("2006-01-02"), now, "2020-05-10"},
+		{TimeFormatter("2006-01-02"), time.Time{}, ""},
+		{relative, now.Add(-200 * time.Second), "3m ago"},
+		{relative, now.Add(50 * time.Hour), "in 2d"},
+		{relative, now.Add(-500 * time.Millisecond), "now"},
+		{DurationFormatter, 51*time.Hour + 10*time.Minute, "2d3h"},
+		{DurationFormatter, 2*time.Hour + 5*time.Second, "2h"},
+		{DurationFormatter, -310 * time.Second, "-5m10s"},
+		{DurationFormatter, 1500 * time.Microsecond, "1.5ms"},
+		{HexFormatter, []byte("\x01\xab"), "01ab"},
+		{Base64Formatter, []byte("hello"), "aGVsbG8="},
+		{ThousandsFormatter(","), 123, "123"},
+		{ThousandsFormatter(","), -1234, "-1,234"},
+		{ThousandsFormatter("."), uint64(12345678), "12.345.678"},
+		{ThousandsFormatter(","), 1.5, "1.5"},
+		{BoolFormatter("yes", "no"), true, "yes"},
+		{BoolFormatter("✓", "✗"), false, "✗"},
+		{BoolFormatter("yes", "no"), "true", "true"},
+	} {
+		if result := test.formatter("", test.data, defaultFormatter); result != test.expected {
+			t.Errorf("Format(%#v) = %q, expected %q", test.data, result, test.expected)
+		}
+	}
+}
+
+func TestTablePrintTypeFormatters(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{
+			Writer: buf,
+			TypeFormatters: NewTypeFormatters().
+				Register(0, ThousandsFormatter(",")).
+				Register(false, BoolFormatter("yes", "no")),
+			Formatter: func(class string, data interface{}, formatter FormatterFunc) string {
+				if class == "table:row:name" {
+					return "<" + formatter(class, data, nil) + ">"
+				}
+				return formatter(class, data, nil)
+			},
+		},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Size", Field: "size", Align: AlignRight},
+			Column{Title: "Done", Field: "done"},
+		},
+		Border: TestBorder,
+	}
+	tv.Print([]map[string]interface{}{
+		{"name": "a", "size": 1234567, "done": true},
+		{"name": "b", "size": 12, "done": false},
+	})
+	result := buf.String()
+	if result != ""+
+		"+----+---------+----+\n"+
+		"|Name|     Size|Done|\n"+
+		"+----+---------+----+\n"+
+		"|<a> |1,234,567|yes |\n"+
+		"+----+---------+----+\n"+
+		"|<b> |       12|no  |\n"+
+		"+----+---------+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
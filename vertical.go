@@ -0,0 +1,86 @@
+package cliview
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Table layouts
+const (
+	LayoutHorizontal = 0 // one line per row
+	LayoutVertical   = 1 // one block of "Title: value" lines per row
+	LayoutAuto       = 2 // vertical if the shrunk horizontal layout doesn't fit MaxWidth
+)
+
+// printVertical prints each row as a block of aligned "Title: value" lines
+// headed by a record line, like the expanded display of psql. Aggregates are
+// printed as a last block, groups and column groups are not shown.
+func (r *tableRender) printVertical(grid [][]tableCell) {
+	tv, columns, maxWidth := r.view, r.columns, r.maxWidth
+	keyWidth := 0
+	for _, col := range columns {
+		if w := textWidth(tv.title(col)); w > keyWidth {
+			keyWidth = w
+		}
+	}
+	valWidth := 0 // unlimited
+	if maxWidth > 0 {
+		if valWidth = maxWidth - tv.Padding - keyWidth - 2; valWidth < 1 {
+			valWidth = 1
+		}
+	}
+
+	printBlock := func(header string, headerData interface{}, classPrefix string, vals []interface{}, strs []string) {
+		lines := make([]string, 0, len(columns))
+		width := textWidth(header) + 2
+		for i, col := range columns {
+			if classPrefix == "table:foot:" && col.Aggregate == nil {
+				continue
+			}
+			var texts []string
+			switch {
+			case valWidth > 0:
+				texts = cellLines(strs[i], valWidth, col.Wrap)
+			case col.Wrap:
+				texts = strings.Split(strs[i], "\n")
+			default:
+				texts = []string{strs[i]}
+			}
+			title := tv.title(col)
+			key := r.style("table:head:"+col.Field, title, col.Title, nil) + ":" + PaddingString(keyWidth-textWidth(title)+1)
+			for n, text := range texts {
+				if n > 0 {
+					key = PaddingString(keyWidth + 2)
+				}
+				if w := keyWidth + 2 + textWidth(text); w > width {
+					width = w
+				}
+				line := key + r.style(classPrefix+col.Field, text, vals[i], col.Styler)
+				lines = append(lines, strings.TrimRight(line, " "))
+			}
+		}
+		if maxWidth > 0 && width > maxWidth-tv.Padding {
+			width = maxWidth - tv.Padding
+		}
+		dashes := ""
+		if n := width - textWidth(header) - 2; n > 0 {
+			dashes = strings.Repeat("-", n)
+		}
+		fmt.Fprintln(r.w, tv.PaddingString()+r.style("table:record:", "-"+header+"-"+dashes, headerData, nil))
+		for _, line := range lines {
+			fmt.Fprintln(r.w, tv.PaddingString()+line)
+		}
+	}
+
+	for n, cells := range grid {
+		vals := make([]interface{}, len(columns))
+		strs := make([]string, len(columns))
+		for i, cell := range cells {
+			vals[i], strs[i] = cell.value, cell.text
+		}
+		printBlock(fmt.Sprintf("[ RECORD %d ]", n+1), n+1, "table:row:", vals, strs)
+	}
+	if vals, strs := tv.aggregate(columns, grid, "table:foot:"); vals != nil {
+		printBlock("[ TOTAL ]", nil, "table:foot:", vals, strs)
+	}
+}
@@ -0,0 +1,95 @@
+package cliview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTablePrintVertical(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Description", Field: "desc", Wrap: true},
+			Column{Title: "Size", Field: "size", Aggregate: AggregateSum},
+			Column{Title: "Hidden", Field: "hidden"},
+		},
+		Border:   TestBorder,
+		MaxWidth: 30,
+		Layout:   LayoutVertical,
+	}
+	tv.HideColumns("hidden")
+	tv.Print([]map[string]interface{}{
+		{"name": "foo", "desc": "a short one", "size": 1},
+		{"name": "bar", "desc": "a longer description to be wrapped", "size": 20},
+	})
+	result := buf.String()
+	if result != ""+
+		"-[ RECORD 1 ]-----------\n"+
+		"Name:        foo\n"+
+		"Description: a short one\n"+
+		"Size:        1\n"+
+		"-[ RECORD 2 ]-----------------\n"+
+		"Name:        bar\n"+
+		"Description: a longer\n"+
+		"             description to be\n"+
+		"             wrapped\n"+
+		"Size:        20\n"+
+		"-[ TOTAL ]-----\n"+
+		"Size:        21\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
+
+func TestTablePrintLayoutAuto(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tv := &Table{
+		Output: Output{Writer: buf},
+		Columns: []Column{
+			Column{Title: "Name", Field: "name"},
+			Column{Title: "Size", Field: "size"},
+		},
+		Border:   TestBorder,
+		MaxWidth: 11,
+		Layout:   LayoutAuto,
+	}
+	data := []map[string]interface{}{
+		{"name": "foo", "size": 1},
+	}
+	tv.Print(data)
+	result := buf.String()
+	if result != ""+
+		"+----+----+\n"+
+		"|Name|Size|\n"+
+		"+----+----+\n"+
+		"|foo |1   |\n"+
+		"+----+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	// shrinking the columns is preferred to the vertical layout
+	buf.Reset()
+	tv.MaxWidth = 10
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"+---+----+\n"+
+		"|N..|Size|\n"+
+		"+---+----+\n"+
+		"|foo|1   |\n"+
+		"+---+----+\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+
+	buf.Reset()
+	tv.MaxWidth = 8
+	tv.Print(data)
+	result = buf.String()
+	if result != ""+
+		"-[ RECORD 1 ]-\n"+
+		"Name: f.\n"+
+		"Size: 1\n" {
+		t.Errorf("Unexpected output\n%v", result)
+	}
+}
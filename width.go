@@ -0,0 +1,297 @@
+package cliview
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges lists the East Asian Wide and Fullwidth code points, including
+// emoji with default emoji presentation.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18aff}, {0x1b000, 0x1b16f}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f202}, {0x1f210, 0x1f23b},
+	{0x1f240, 0x1f248}, {0x1f250, 0x1f251}, {0x1f260, 0x1f265}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc}, {0x1f7e0, 0x1f7eb},
+	{0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945}, {0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+func isWide(r rune) bool {
+	i := sort.Search(len(wideRanges), func(i int) bool {
+		return wideRanges[i][1] >= r
+	})
+	return i < len(wideRanges) && wideRanges[i][0] <= r
+}
+
+// isExtender reports whether r attaches to the preceding character in the
+// same grapheme cluster.
+func isExtender(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == 0x200c || r == 0x200d || // ZWNJ, ZWJ
+		(r >= 0x1160 && r <= 0x11ff) || // Hangul medial vowels and final consonants
+		(r >= 0xfe00 && r <= 0xfe0f) || // variation selectors
+		(r >= 0x1f3fb && r <= 0x1f3ff) || // emoji skin tone modifiers
+		(r >= 0xe0020 && r <= 0xe007f) || // tags
+		(r >= 0xe0100 && r <= 0xe01ef)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// runeWidth returns the number of terminal cells occupied by r.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1
+	case isExtender(r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// nextCluster returns the size in bytes and the display width of the
+// grapheme cluster at the beginning of s.
+func nextCluster(s string) (size, width int) {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 {
+		return 0, 0
+	}
+	size, width = n, runeWidth(r)
+	if isRegionalIndicator(r) {
+		if r2, n2 := utf8.DecodeRuneInString(s[size:]); isRegionalIndicator(r2) {
+			return size + n2, 2
+		}
+	}
+	joined := r == 0x200d
+	for size < len(s) {
+		r2, n2 := utf8.DecodeRuneInString(s[size:])
+		if !joined && !isExtender(r2) {
+			break
+		}
+		if r2 == 0xfe0f && width == 1 {
+			width = 2 // emoji presentation
+		}
+		joined = r2 == 0x200d
+		size += n2
+	}
+	return size, width
+}
+
+// escapeLen returns the length in bytes of the ANSI escape sequence at the
+// beginning of s, or 0 if s does not start with one.
+func escapeLen(s string) int {
+	if len(s) == 0 || s[0] != 0x1b {
+		return 0
+	}
+	if len(s) == 1 {
+		return 1
+	}
+	switch s[1] {
+	case '[': // CSI: parameters, intermediates and a final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_': // OSC and other strings terminated by BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// nextSegment returns the size in bytes and the display width of the
+// escape sequence or grapheme cluster at the beginning of s.
+func nextSegment(s string) (size, width int) {
+	if n := escapeLen(s); n > 0 {
+		return n, 0
+	}
+	return nextCluster(s)
+}
+
+// ansiState tracks the styling left open by SGR and OSC 8 hyperlink
+// sequences.
+type ansiState struct {
+	sgr  []string
+	link string
+}
+
+func (st *ansiState) update(seq string) {
+	switch {
+	case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+		params := seq[2 : len(seq)-1]
+		if params == "" || strings.Trim(params, "0") == "" {
+			st.sgr = nil
+		} else if strings.HasPrefix(params, "0;") {
+			st.sgr = []string{seq}
+		} else {
+			st.sgr = append(st.sgr, seq)
+		}
+	case strings.HasPrefix(seq, "\x1b]8;"):
+		params := strings.TrimRight(strings.TrimPrefix(seq, "\x1b]8;"), "\x07\x1b\\")
+		if i := strings.Index(params, ";"); i >= 0 && params[i+1:] != "" {
+			st.link = seq
+		} else {
+			st.link = ""
+		}
+	}
+}
+
+// closing returns the sequences which end the open styling.
+func (st *ansiState) closing() string {
+	s := ""
+	if len(st.sgr) > 0 {
+		s += "\x1b[0m"
+	}
+	if st.link != "" {
+		s += "\x1b]8;;\x1b\\"
+	}
+	return s
+}
+
+// opening returns the sequences which restore the open styling.
+func (st *ansiState) opening() string {
+	return st.link + strings.Join(st.sgr, "")
+}
+
+// textWidth returns the number of terminal cells needed to display text.
+// ANSI escape sequences do not take any space.
+func textWidth(text string) int {
+	width := 0
+	for len(text) > 0 {
+		size, w := nextSegment(text)
+		width += w
+		text = text[size:]
+	}
+	return width
+}
+
+// cutWidth finds the longest prefix of text which fits in width without
+// breaking grapheme clusters or escape sequences. It returns the length of
+// the prefix in bytes, its display width and the styling open at its end.
+func cutWidth(text string, width int) (int, int, ansiState) {
+	var st ansiState
+	pos, w := 0, 0
+	for pos < len(text) {
+		size, cw := nextSegment(text[pos:])
+		if w+cw > width {
+			break
+		}
+		if cw == 0 && size > 0 && text[pos] == 0x1b {
+			st.update(text[pos : pos+size])
+		}
+		pos += size
+		w += cw
+	}
+	return pos, w, st
+}
+
+// truncateWidth returns the longest prefix of text which fits in width
+// along with its display width. Styling left open by escape sequences in
+// the prefix is closed.
+func truncateWidth(text string, width int) (string, int) {
+	pos, w, st := cutWidth(text, width)
+	if pos < len(text) {
+		return text[:pos] + st.closing(), w
+	}
+	return text, w
+}
+
+// splitWidth splits text so the head is not wider than width. The head
+// contains at least one grapheme cluster so the split always makes progress.
+// Styling open at the split point is closed in the head and restored in the
+// tail. The tail is empty if only escape sequences are left for it.
+func splitWidth(text string, width int) (string, string) {
+	pos, w, st := cutWidth(text, width)
+	if w == 0 {
+		// the first visible cluster is too wide, take it with the escape
+		// sequences in front of it
+		pos, st = 0, ansiState{}
+		for pos < len(text) {
+			size, cw := nextSegment(text[pos:])
+			if cw == 0 && size > 0 && text[pos] == 0x1b {
+				st.update(text[pos : pos+size])
+			}
+			pos += size
+			if cw > 0 || size == 0 {
+				break
+			}
+		}
+	}
+	if textWidth(text[pos:]) == 0 {
+		// only escape sequences are left, which end the head
+		return text, ""
+	}
+	return text[:pos] + st.closing(), st.opening() + text[pos:]
+}
+
+// balanceStyles makes each line self-contained: styling open at the end of
+// a line is closed there and restored at the beginning of the next line.
+func balanceStyles(lines []string) []string {
+	var st ansiState
+	for i, line := range lines {
+		open := st.opening()
+		for pos := 0; pos < len(line); {
+			size, _ := nextSegment(line[pos:])
+			if line[pos] == 0x1b {
+				st.update(line[pos : pos+size])
+			}
+			pos += size
+		}
+		lines[i] = open + line + st.closing()
+	}
+	return lines
+}
+
+func ellipsis(text string, width int) string {
+	if textWidth(text) <= width {
+		return text
+	}
+	if width <= 3 {
+		first := 0
+		for rest := text; len(rest) > 0 && first == 0; {
+			size, w := nextSegment(rest)
+			first = w
+			rest = rest[size:]
+		}
+		if first > width {
+			first = 0
+		}
+		head, w := truncateWidth(text, first)
+		return head + strings.Repeat(".", width-w)
+	}
+	head, _ := truncateWidth(text, width-3)
+	return head + "..."
+}
@@ -0,0 +1,122 @@
+package cliview
+
+import (
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	cases := map[string]int{
+		"":                         0,
+		"abc":                      3,
+		"\u4e2d\u6587":             4, // 中文
+		"\uff46\uff55\uff4c\uff4c": 8, // fullwidth "full"
+		"e\u0301":                  1, // combining acute accent
+		"\u200b":                   0, // zero width space
+		"\U0001f44d":               2, // thumbs up
+		"\U0001f44d\U0001f3fd":     2, // with skin tone
+		"\U0001f468\u200d\U0001f469\u200d\U0001f467": 2, // family ZWJ sequence
+		"\U0001f1e8\U0001f1f3":                       2, // flag
+		"\u2764\ufe0f":                               2, // heart with emoji presentation
+		"\ud55c\uad6d\uc5b4":                         6, // 한국어
+	}
+	for text, width := range cases {
+		if w := textWidth(text); w != width {
+			t.Errorf("textWidth(%q) = %d, expect %d", text, w, width)
+		}
+	}
+}
+
+func TestEllipsisWide(t *testing.T) {
+	cases := []struct {
+		text   string
+		width  int
+		expect string
+	}{
+		{"中文名称", 8, "中文名称"},
+		{"中文名称", 7, "中文..."},
+		{"中文名称", 6, "中..."},
+		{"中文名称", 2, "中"},
+		{"中文名称", 1, "."},
+		{"cafés", 7, "cafés"},
+		{"cafés", 4, "c..."},
+		{"xéyz", 3, "x.."},
+		{"abécdef", 6, "abé..."},
+	}
+	for _, c := range cases {
+		if s := ellipsis(c.text, c.width); s != c.expect {
+			t.Errorf("ellipsis(%q, %d) = %q, expect %q", c.text, c.width, s, c.expect)
+		}
+	}
+}
+
+func TestWrapTextWide(t *testing.T) {
+	lines := wrapText("日本語のテキスト", 5)
+	expect := []string{"日本", "語の", "テキ", "スト"}
+	if len(lines) != len(expect) {
+		t.Fatalf("Unexpected lines %q", lines)
+	}
+	for i := range lines {
+		if lines[i] != expect[i] {
+			t.Errorf("Unexpected lines %q", lines)
+		}
+	}
+}
+
+func TestTextWidthEscapes(t *testing.T) {
+	cases := map[string]int{
+		"\x1b[31mred\x1b[0m":                               3,
+		"\x1b[1;38;5;208m中\x1b[m":                          2,
+		"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x07": 4,
+		"\x1b": 0,
+	}
+	for text, width := range cases {
+		if w := textWidth(text); w != width {
+			t.Errorf("textWidth(%q) = %d, expect %d", text, w, width)
+		}
+	}
+}
+
+func TestEllipsisEscapes(t *testing.T) {
+	cases := []struct {
+		text   string
+		width  int
+		expect string
+	}{
+		{"\x1b[31mred\x1b[0m", 3, "\x1b[31mred\x1b[0m"},
+		{"\x1b[31mabcdef\x1b[0m", 5, "\x1b[31mab\x1b[0m..."},
+		{"ab\x1b[1mcdef\x1b[0m", 4, "a..."},
+		{"ab\x1b[1mcdef\x1b[0m", 5, "ab\x1b[1m\x1b[0m..."},
+		{"\x1b[31mabcdef\x1b[0m", 2, "\x1b[31ma\x1b[0m."},
+		{"\x1b]8;;http://x\x1b\\abcdef\x1b]8;;\x1b\\", 4, "\x1b]8;;http://x\x1b\\a\x1b]8;;\x1b\\..."},
+	}
+	for _, c := range cases {
+		if s := ellipsis(c.text, c.width); s != c.expect {
+			t.Errorf("ellipsis(%q, %d) = %q, expect %q", c.text, c.width, s, c.expect)
+		}
+	}
+}
+
+func TestWrapTextEscapes(t *testing.T) {
+	lines := wrapText("\x1b[32mgreen text\x1b[0m plain", 5)
+	expect := []string{"\x1b[32mgreen\x1b[0m", "\x1b[32mtext\x1b[0m", "plain"}
+	if len(lines) != len(expect) {
+		t.Fatalf("Unexpected lines %q", lines)
+	}
+	for i := range lines {
+		if lines[i] != expect[i] {
+			t.Errorf("Unexpected lines %q", lines)
+		}
+	}
+
+	// a wide rune after an escape sequence doesn't fit, but is still taken
+	lines = wrapText("\x1b[1m名前\x1b[0m", 1)
+	expect = []string{"\x1b[1m名\x1b[0m", "\x1b[1m前\x1b[0m"}
+	if len(lines) != len(expect) {
+		t.Fatalf("Unexpected lines %q", lines)
+	}
+	for i := range lines {
+		if lines[i] != expect[i] {
+			t.Errorf("Unexpected lines %q", lines)
+		}
+	}
+}